@@ -0,0 +1,175 @@
+package animation
+
+import "fmt"
+
+// EventListener is notified when a clip enters a frame with an event.
+type EventListener interface {
+	AnimationEvent(clip *Clip, event string, frame int)
+}
+
+// Animator plays clips from a ClipSet.
+type Animator struct {
+	clips *ClipSet
+
+	clip *Clip
+
+	frame     int
+	direction int
+	elapsed   float32
+
+	playing  bool
+	finished bool
+
+	// Speed scales time, 1.0 = normal speed.
+	Speed float32
+
+	listener EventListener
+}
+
+// NewAnimator creates an Animator for a ClipSet
+func NewAnimator(clips *ClipSet) *Animator {
+	a := new(Animator)
+	a.clips = clips
+	a.Speed = 1.0
+	return a
+}
+
+// SetListener sets the event listener. nil removes it.
+func (a *Animator) SetListener(listener EventListener) {
+	a.listener = listener
+}
+
+// Play starts a clip from its first frame. Playing the current clip
+// again restarts it.
+func (a *Animator) Play(name string) error {
+	c := a.clips.Clip(name)
+	if c == nil {
+		return fmt.Errorf("unknown clip '%s'", name)
+	}
+
+	a.clip = c
+	a.frame = 0
+	a.direction = 1
+	a.elapsed = 0.0
+	a.playing = true
+	a.finished = false
+
+	a.fire()
+
+	return nil
+}
+
+// Stop halts playback on the current frame.
+func (a *Animator) Stop() {
+	a.playing = false
+}
+
+// Resume continues playback of a stopped clip.
+func (a *Animator) Resume() {
+	if a.clip != nil && !a.finished {
+		a.playing = true
+	}
+}
+
+// IsPlaying indicates if the clip is advancing.
+func (a *Animator) IsPlaying() bool {
+	return a.playing
+}
+
+// IsFinished indicates a Once clip has reached its last frame.
+func (a *Animator) IsFinished() bool {
+	return a.finished
+}
+
+// Clip returns the current clip or nil
+func (a *Animator) Clip() *Clip {
+	return a.clip
+}
+
+// FrameIndex returns the current frame's index.
+func (a *Animator) FrameIndex() int {
+	return a.frame
+}
+
+// Frame returns the current frame's name or "" if nothing is playing.
+func (a *Animator) Frame() string {
+	if a.clip == nil {
+		return ""
+	}
+	return a.clip.Frames[a.frame]
+}
+
+// Update advances by dt seconds. It returns true if the frame changed.
+func (a *Animator) Update(dt float32) bool {
+	if !a.playing || a.clip == nil {
+		return false
+	}
+
+	a.elapsed += dt * a.Speed
+
+	changed := false
+
+	// A large dt can pass several frames and each fires its event.
+	for a.playing && a.elapsed >= a.clip.Duration(a.frame) {
+		a.elapsed -= a.clip.Duration(a.frame)
+
+		if a.advance() {
+			changed = true
+			a.fire()
+		}
+	}
+
+	return changed
+}
+
+// advance moves to the next frame according to the play mode. It returns
+// false if the frame didn't change.
+func (a *Animator) advance() bool {
+	last := len(a.clip.Frames) - 1
+
+	if last == 0 {
+		if a.clip.Mode == Once {
+			a.finish()
+		}
+		return false
+	}
+
+	next := a.frame + a.direction
+
+	switch a.clip.Mode {
+	case Loop:
+		if next > last {
+			next = 0
+		}
+	case PingPong:
+		if next > last || next < 0 {
+			a.direction = -a.direction
+			next = a.frame + a.direction
+		}
+	case Once:
+		if next > last {
+			a.finish()
+			return false
+		}
+	}
+
+	a.frame = next
+
+	return true
+}
+
+func (a *Animator) finish() {
+	a.playing = false
+	a.finished = true
+	a.elapsed = 0.0
+}
+
+func (a *Animator) fire() {
+	if a.listener == nil {
+		return
+	}
+
+	if event, ok := a.clip.Events[a.frame]; ok {
+		a.listener.AnimationEvent(a.clip, event, a.frame)
+	}
+}
@@ -0,0 +1,118 @@
+package animation
+
+import (
+	"testing"
+)
+
+const testClips = `{
+  "Clips": [
+    {"Name": "run", "Frames": ["r0", "r1", "r2"], "Durations": [0.1], "Mode": "Loop", "Events": {"1": "footstep"}},
+    {"Name": "bob", "Frames": ["b0", "b1", "b2"], "Durations": [0.1, 0.2, 0.1], "Mode": "PingPong"},
+    {"Name": "die", "Frames": ["d0", "d1"], "Durations": [0.1], "Mode": "Once", "Events": {"1": "dead"}}
+  ]
+}`
+
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) AnimationEvent(clip *Clip, event string, frame int) {
+	r.events = append(r.events, event)
+}
+
+func newTestAnimator(t *testing.T) *Animator {
+	cs, err := ParseClips([]byte(testClips))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewAnimator(cs)
+}
+
+func Test_Animator_Loop(t *testing.T) {
+	a := newTestAnimator(t)
+	a.Play("run")
+
+	if a.Frame() != "r0" {
+		t.Errorf("Expected r0, got: %s", a.Frame())
+	}
+
+	a.Update(0.15)
+	if a.Frame() != "r1" {
+		t.Errorf("Expected r1, got: %s", a.Frame())
+	}
+
+	a.Update(0.2)
+	if a.Frame() != "r0" {
+		t.Errorf("Expected loop back to r0, got: %s", a.Frame())
+	}
+}
+
+func Test_Animator_PingPong(t *testing.T) {
+	a := newTestAnimator(t)
+	a.Play("bob")
+
+	expected := []string{"b1", "b2", "b1", "b0", "b1"}
+	durations := []float32{0.1, 0.2, 0.1, 0.2, 0.1}
+
+	for i, d := range durations {
+		a.Update(d)
+		if a.Frame() != expected[i] {
+			t.Errorf("Step %d: expected %s, got: %s", i, expected[i], a.Frame())
+		}
+	}
+}
+
+func Test_Animator_OnceFinishes(t *testing.T) {
+	a := newTestAnimator(t)
+	r := &recorder{}
+	a.SetListener(r)
+	a.Play("die")
+
+	a.Update(1.0)
+
+	if !a.IsFinished() || a.IsPlaying() {
+		t.Error("Expected clip to be finished")
+	}
+
+	if a.Frame() != "d1" {
+		t.Errorf("Expected to stop on d1, got: %s", a.Frame())
+	}
+
+	if len(r.events) != 1 || r.events[0] != "dead" {
+		t.Errorf("Expected one 'dead' event, got: %v", r.events)
+	}
+}
+
+func Test_Animator_EventsOnLargeStep(t *testing.T) {
+	a := newTestAnimator(t)
+	r := &recorder{}
+	a.SetListener(r)
+	a.Play("run")
+
+	// Passes frame 1 twice: r1, r2, r0, r1
+	a.Update(0.4)
+
+	if len(r.events) != 2 {
+		t.Errorf("Expected 2 footsteps, got: %v", r.events)
+	}
+}
+
+func Test_Animator_UnknownClip(t *testing.T) {
+	a := newTestAnimator(t)
+
+	if a.Play("fly") == nil {
+		t.Error("Expected an error for an unknown clip")
+	}
+}
+
+func Test_ParseClips_Invalid(t *testing.T) {
+	_, err := ParseClips([]byte(`{"Clips": [{"Name": "x", "Frames": ["a", "b"], "Durations": [0.1, 0.1, 0.1]}]}`))
+	if err == nil {
+		t.Error("Expected an error for mismatched durations")
+	}
+
+	_, err = ParseClips([]byte(`{"Clips": [{"Name": "x", "Frames": ["a"], "Durations": [0.1], "Mode": "Bounce"}]}`))
+	if err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+}
@@ -0,0 +1,166 @@
+// Package animation provides frame-by-frame (aka flipbook) animation clips.
+package animation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// PlayMode determines what happens when a clip reaches its last frame.
+type PlayMode int
+
+const (
+	// Loop restarts at the first frame
+	Loop PlayMode = iota
+	// PingPong reverses direction at either end
+	PingPong
+	// Once stops on the last frame
+	Once
+)
+
+var playModeNames = map[string]PlayMode{
+	"Loop":     Loop,
+	"PingPong": PingPong,
+	"Once":     Once,
+}
+
+// UnmarshalJSON accepts "Loop", "PingPong" or "Once"
+func (pm *PlayMode) UnmarshalJSON(data []byte) error {
+	var name string
+	err := json.Unmarshal(data, &name)
+	if err != nil {
+		return err
+	}
+
+	mode, ok := playModeNames[name]
+	if !ok {
+		return fmt.Errorf("unknown play mode '%s'", name)
+	}
+
+	*pm = mode
+	return nil
+}
+
+// MarshalJSON writes the mode's name
+func (pm PlayMode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(pm.String())
+}
+
+func (pm PlayMode) String() string {
+	for name, mode := range playModeNames {
+		if mode == pm {
+			return name
+		}
+	}
+	return "Unknown"
+}
+
+// Clip is a named sequence of frames, for example, sprite sheet frame names.
+type Clip struct {
+	Name   string
+	Frames []string
+	// Durations are per frame in seconds. A single duration applies to
+	// every frame.
+	Durations []float32
+	Mode      PlayMode
+	// Events are fired when a frame (by index) is entered.
+	Events map[int]string
+}
+
+// Duration returns how long a frame is shown.
+func (c *Clip) Duration(frame int) float32 {
+	if len(c.Durations) == 1 {
+		return c.Durations[0]
+	}
+	return c.Durations[frame]
+}
+
+// Validate checks that a clip can be played.
+func (c *Clip) Validate() error {
+	if len(c.Frames) == 0 {
+		return fmt.Errorf("clip '%s' has no frames", c.Name)
+	}
+
+	if len(c.Durations) != 1 && len(c.Durations) != len(c.Frames) {
+		return fmt.Errorf("clip '%s' needs 1 or %d durations, has %d", c.Name, len(c.Frames), len(c.Durations))
+	}
+
+	for _, d := range c.Durations {
+		if d <= 0.0 {
+			return fmt.Errorf("clip '%s' has a non-positive duration", c.Name)
+		}
+	}
+
+	for frame := range c.Events {
+		if frame < 0 || frame >= len(c.Frames) {
+			return fmt.Errorf("clip '%s' has an event on missing frame %d", c.Name, frame)
+		}
+	}
+
+	return nil
+}
+
+// ClipSet is a map-collection of clips.
+type ClipSet struct {
+	Clips map[string]*Clip
+}
+
+type clipFile struct {
+	Clips []*Clip
+}
+
+// NewClipSet creates an empty ClipSet
+func NewClipSet() *ClipSet {
+	cs := new(ClipSet)
+	cs.Clips = make(map[string]*Clip)
+	return cs
+}
+
+// LoadClips reads clips from a JSON file formatted as:
+//   {"Clips": [{"Name": "run", "Frames": ["run/0", "run/1"],
+//     "Durations": [0.1], "Mode": "Loop", "Events": {"1": "footstep"}}]}
+func LoadClips(path string) (*ClipSet, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseClips(bytes)
+}
+
+// ParseClips reads clips from JSON. See LoadClips.
+func ParseClips(data []byte) (*ClipSet, error) {
+	var file clipFile
+	err := json.Unmarshal(data, &file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse clips: %s", err.Error())
+	}
+
+	cs := NewClipSet()
+
+	for _, c := range file.Clips {
+		err = cs.Add(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cs, nil
+}
+
+// Add validates and adds a clip.
+func (cs *ClipSet) Add(c *Clip) error {
+	err := c.Validate()
+	if err != nil {
+		return err
+	}
+
+	cs.Clips[c.Name] = c
+	return nil
+}
+
+// Clip returns a clip by name or nil.
+func (cs *ClipSet) Clip(name string) *Clip {
+	return cs.Clips[name]
+}
@@ -0,0 +1,161 @@
+// Command atlaspacker packs a directory of images into sprite sheet pages
+// with a JSON descriptor that atlas.LoadSpriteSheet reads.
+//
+// Usage:
+//   atlaspacker -in ./sprites -out ./assets -name hero
+//
+// Frames are named after their file path relative to -in without the
+// extension, for example, "run/0".
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	// Register decoders for image.Decode
+	_ "image/jpeg"
+
+	"github.com/wdevore/ranger/rendering/atlas"
+)
+
+func main() {
+	in := flag.String("in", ".", "Directory of .png/.jpg images to pack")
+	out := flag.String("out", ".", "Directory to write pages and the descriptor to")
+	name := flag.String("name", "atlas", "Base name of the page images and descriptor")
+	maxSize := flag.Int("size", 2048, "Maximum page width and height")
+	padding := flag.Int("padding", 2, "Empty pixels around each image")
+	trim := flag.Bool("trim", true, "Trim fully transparent borders")
+	pot := flag.Bool("pot", true, "Round page dimensions up to a power of two")
+	flag.Parse()
+
+	options := atlas.PackOptions{
+		MaxWidth:   *maxSize,
+		MaxHeight:  *maxSize,
+		Padding:    *padding,
+		Trim:       *trim,
+		PowerOfTwo: *pot,
+	}
+
+	err := run(*in, *out, *name, options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "atlaspacker: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+func run(in, out, name string, options atlas.PackOptions) error {
+	images, err := readImages(in)
+	if err != nil {
+		return err
+	}
+
+	if len(images) == 0 {
+		return fmt.Errorf("no images found in '%s'", in)
+	}
+
+	pages, err := atlas.Pack(images, options)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(out, 0755)
+	if err != nil {
+		return err
+	}
+
+	var desc atlas.SheetDescriptor
+
+	for i, page := range pages {
+		pageName := fmt.Sprintf("%s_%d.png", name, i)
+
+		err = writePNG(filepath.Join(out, pageName), page.Image)
+		if err != nil {
+			return err
+		}
+
+		bounds := page.Image.Bounds()
+		desc.Pages = append(desc.Pages, atlas.PageDescriptor{
+			Image:  pageName,
+			Width:  bounds.Dx(),
+			Height: bounds.Dy(),
+		})
+
+		desc.Frames = append(desc.Frames, page.Frames...)
+	}
+
+	// A stable order keeps descriptors diff friendly.
+	sort.Slice(desc.Frames, func(i, j int) bool {
+		return desc.Frames[i].Name < desc.Frames[j].Name
+	})
+
+	bytes, err := json.MarshalIndent(&desc, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	descPath := filepath.Join(out, name+".json")
+	err = ioutil.WriteFile(descPath, bytes, 0644)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Packed %d frames into %d page(s): %s\n", len(desc.Frames), len(pages), descPath)
+
+	return nil
+}
+
+func readImages(dir string) ([]atlas.NamedImage, error) {
+	images := []atlas.NamedImage{}
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		ext := strings.ToLower(filepath.Ext(path))
+		if info.IsDir() || (ext != ".png" && ext != ".jpg" && ext != ".jpeg") {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		img, _, err := image.Decode(file)
+		if err != nil {
+			return fmt.Errorf("failed to decode '%s': %s", path, err.Error())
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		frameName := filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel)))
+		images = append(images, atlas.NamedImage{Name: frameName, Image: img})
+
+		return nil
+	})
+
+	return images, err
+}
+
+func writePNG(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return png.Encode(file, img)
+}
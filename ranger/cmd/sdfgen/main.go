@@ -0,0 +1,104 @@
+// Command sdfgen generates a signed distance field font from a TTF as
+// AngelCode BMFont pages and a text .fnt descriptor that
+// rendering.LoadBMFont reads.
+//
+// Usage:
+//   sdfgen -ttf ./Roboto.ttf -size 48 -range 8 -out ./assets -name roboto
+//
+// Without -ttf the embedded Neuropol font is used.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/wdevore/ranger/fonts"
+)
+
+func main() {
+	ttfPath := flag.String("ttf", "", "TrueType font to convert. Empty uses Neuropol")
+	out := flag.String("out", ".", "Directory to write pages and the descriptor to")
+	name := flag.String("name", "sdf", "Base name of the page images and descriptor")
+	size := flag.Int("size", 48, "Pixel size glyphs are generated at")
+	distanceRange := flag.Int("range", fonts.DefaultDistanceRange, "Distance, in pixels, the field spans")
+	chars := flag.Int("chars", 127, "Generate runes from space up to, but excluding, this one")
+	flag.Parse()
+
+	err := run(*ttfPath, *out, *name, *size, *distanceRange, *chars)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sdfgen: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+func run(ttfPath, out, name string, size, distanceRange, chars int) error {
+	var ttf []byte
+	var err error
+
+	if ttfPath == "" {
+		ttf, err = fonts.Neuropol()
+	} else {
+		ttf, err = ioutil.ReadFile(ttfPath)
+	}
+	if err != nil {
+		return err
+	}
+
+	if distanceRange < 1 {
+		return fmt.Errorf("range must be at least 1, got: %d", distanceRange)
+	}
+
+	ga, err := fonts.NewSDFAtlas(name, ttf, float32(size), distanceRange, fonts.CharSet(chars))
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(out, 0755)
+	if err != nil {
+		return err
+	}
+
+	pageNames := []string{}
+
+	for i, page := range ga.Pages {
+		pageName := fmt.Sprintf("%s_%d.png", name, i)
+
+		err = writePNG(filepath.Join(out, pageName), page)
+		if err != nil {
+			return err
+		}
+
+		pageNames = append(pageNames, pageName)
+	}
+
+	fntPath := filepath.Join(out, name+".fnt")
+	file, err := os.Create(fntPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = fonts.NewBMFont(ga, pageNames).WriteText(file)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Generated %d glyphs on %d page(s): %s\n", len(ga.Glyphs), len(ga.Pages), fntPath)
+
+	return nil
+}
+
+func writePNG(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return png.Encode(file, img)
+}
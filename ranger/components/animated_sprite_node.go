@@ -0,0 +1,79 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/wdevore/ranger/animation"
+	"github.com/wdevore/ranger/rendering"
+	"github.com/wdevore/ranger/rendering/atlas"
+	"github.com/wdevore/ranger/rmath"
+)
+
+// AnimatedSpriteNode is a SpriteNode whose frame is driven by animation
+// clips that reference SpriteSheet frames by name.
+type AnimatedSpriteNode struct {
+	SpriteNode
+
+	sheet    *atlas.SpriteSheet
+	Animator *animation.Animator
+}
+
+// NewAnimatedSpriteNode creates a visible AnimatedSpriteNode. Call Play to
+// start a clip.
+func NewAnimatedSpriteNode(renderer *rendering.SpriteRenderer, sheet *atlas.SpriteSheet, clips *animation.ClipSet) *AnimatedSpriteNode {
+	a := new(AnimatedSpriteNode)
+	a.initialize()
+	a.renderer = renderer
+	a.sheet = sheet
+	a.Animator = animation.NewAnimator(clips)
+	return a
+}
+
+// Play starts a clip from its first frame. Every frame of the clip must
+// exist in the sprite sheet.
+func (a *AnimatedSpriteNode) Play(clip string) error {
+	err := a.Animator.Play(clip)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range a.Animator.Clip().Frames {
+		if a.sheet.Frame(name) == nil {
+			a.Animator.Stop()
+			return fmt.Errorf("clip '%s' references missing frame '%s'", clip, name)
+		}
+	}
+
+	return a.syncFrame()
+}
+
+// syncFrame shows the Animator's current frame.
+func (a *AnimatedSpriteNode) syncFrame() error {
+	name := a.Animator.Frame()
+
+	frame := a.sheet.Frame(name)
+	if frame == nil {
+		return fmt.Errorf("sprite sheet has no frame '%s'", name)
+	}
+
+	a.SetFrame(frame)
+
+	return nil
+}
+
+// ---------------------------------------------------------------
+// Node overrides
+// ---------------------------------------------------------------
+
+// Visit advances the animation and renders the current frame.
+func (a *AnimatedSpriteNode) Visit(dt float32, modelT *rmath.Matrix4) bool {
+	if a.Animator.Update(dt) {
+		// Clips are checked against the sheet when played so a missing
+		// frame here means the sheet changed underneath us.
+		if err := a.syncFrame(); err != nil {
+			println(err.Error())
+		}
+	}
+
+	return a.SpriteNode.Visit(dt, modelT)
+}
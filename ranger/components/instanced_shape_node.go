@@ -0,0 +1,90 @@
+package components
+
+import (
+	"github.com/wdevore/ranger/graphics"
+	"github.com/wdevore/ranger/rendering"
+	"github.com/wdevore/ranger/rmath"
+)
+
+// InstancedShapeNode is a Node that draws many copies of one VectorShape,
+// for example, bullets, stars or particles, with a single draw call. Each
+// Instance is a lightweight position, rotation, scale and colour in the
+// Node's local space; they aren't Nodes.
+type InstancedShapeNode struct {
+	Node
+
+	renderer *rendering.InstancedShapeRenderer
+	object   *rendering.VectorObject
+	shape    *rendering.VectorShape
+
+	// Instances of the shape. Add, change and remove them freely, they are
+	// uploaded each time the Node is rendered.
+	Instances *rendering.InstanceBuffer
+
+	// Color tints every instance. Default is White.
+	Color graphics.Colors
+
+	// The model-view-projection computed during Visit.
+	mvp rmath.Matrix4
+}
+
+// NewInstancedShapeNode creates a visible InstancedShapeNode without
+// instances. The vector object must be bound (uploaded) before rendering.
+func NewInstancedShapeNode(renderer *rendering.InstancedShapeRenderer, object *rendering.VectorObject, shape *rendering.VectorShape) *InstancedShapeNode {
+	n := new(InstancedShapeNode)
+	n.initialize()
+	n.renderer = renderer
+	n.object = object
+	n.shape = shape
+	return n
+}
+
+func (n *InstancedShapeNode) initialize() {
+	n.Node.initialize() // super
+	n.Visible = true
+	n.Instances = rendering.NewInstanceBuffer(64)
+	n.Color.SetFromColors(graphics.White)
+}
+
+// SetShape changes the shape drawn for each instance.
+func (n *InstancedShapeNode) SetShape(object *rendering.VectorObject, shape *rendering.VectorShape) {
+	n.object = object
+	n.shape = shape
+}
+
+// AddInstance adds a white, unscaled instance at x,y and returns it. The
+// pointer is valid until instances are added or removed.
+func (n *InstancedShapeNode) AddInstance(x, y float32) *rendering.Instance {
+	i := n.Instances.Add(x, y)
+	return &n.Instances.Instances[i]
+}
+
+// ---------------------------------------------------------------
+// Node overrides
+// ---------------------------------------------------------------
+
+// Visit computes the model-view-projection and renders. 'modelT' is the
+// parent's accumulated transform.
+func (n *InstancedShapeNode) Visit(dt float32, modelT *rmath.Matrix4) bool {
+	if !n.Visible || n.Instances.Len() == 0 {
+		return false
+	}
+
+	// [parent] x [node]
+	rmath.Multiply(modelT, n.CalcTransform(), &n.mvp)
+
+	n.Render()
+
+	return true
+}
+
+// Delete deletes the instance buffer's GL objects. The shape's object is
+// owned by its atlas.
+func (n *InstancedShapeNode) Delete() {
+	n.Instances.Delete()
+}
+
+// Render draws the instances using the transform computed by Visit.
+func (n *InstancedShapeNode) Render() {
+	n.renderer.Draw(n.object, n.shape, n.Instances, &n.mvp, &n.Color)
+}
@@ -115,6 +115,11 @@ func (n *Node) SetPositionByVector(v *rmath.Vector3) {
 	n.transformDirty = true
 }
 
+// Position returns the positional property
+func (n *Node) Position() *rmath.Vector3 {
+	return &n.position
+}
+
 // MoveBy increments positional property
 func (n *Node) MoveBy(v *rmath.Vector3) {
 	n.position.Add(v)
@@ -10,6 +10,15 @@ type Scene interface {
 
 	IsAlive() bool
 	SetAlive(live bool)
+
+	// Release deletes what the Scene owns when it leaves the stage.
+	Release()
+}
+
+// Deleter deletes GL resources, for example, a TextNode, rendering.Font
+// or atlas.SpriteSheet.
+type Deleter interface {
+	Delete()
 }
 
 // SceneBase is a common base for typical scenes.
@@ -20,6 +29,8 @@ type SceneBase struct {
 	stage *ranger.Stage
 
 	alive bool
+
+	owned []Deleter
 }
 
 // IsAlive indicates that the Scene is moving onto the stage or is on the stage.
@@ -32,6 +43,19 @@ func (sb *SceneBase) SetAlive(live bool) {
 	sb.alive = live
 }
 
+// Own makes the Scene delete 'd' when it's released.
+func (sb *SceneBase) Own(d Deleter) {
+	sb.owned = append(sb.owned, d)
+}
+
+// Release deletes the owned resources, newest first.
+func (sb *SceneBase) Release() {
+	for i := len(sb.owned) - 1; i >= 0; i-- {
+		sb.owned[i].Delete()
+	}
+	sb.owned = nil
+}
+
 // NewScene creates a Scene
 // func NewScene(st *ranger.Stage) *Scene {
 // 	s := new(Scene)
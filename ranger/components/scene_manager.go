@@ -32,24 +32,28 @@ func NewSceneManager(max int) *SceneManager {
 
 // Push tells the SceneManager to start interacting with the new Scene.
 func (sm *SceneManager) Push(newSc Scene) {
-	// If there an active scene then get its outward transition and
-	// start it.
+	// If there an active scene then it becomes the outgoing scene and
+	// its outward transition is started.
 	if sm.activeScene != nil {
-		sm.transitionOut = newSc.GetOutTransition()
-		sm.transitionOut.Start()
-	}
+		// The stack top becomes the outgoing scene
+		var isScene bool
 
-	// The stack top becomes the outgoing scene
-	var isScene bool
+		sm.outgoingScene, isScene = sm.scenes.Pop().(Scene)
 
-	sm.outgoingScene, isScene = sm.scenes.Pop().(Scene)
+		if !isScene {
+			panic("Top of scene stack contained something other than a Scene")
+		}
 
-	if !isScene {
-		panic("Top of scene stack contained something other than a Scene")
+		sm.transitionOut = sm.outgoingScene.GetOutTransition()
+		if sm.transitionOut != nil {
+			sm.transitionOut.Start()
+		} else {
+			sm.exitOutgoing()
+		}
 	}
 
 	// Now push the new scene and make it active
-	sm.Push(newSc)
+	sm.scenes.Push(newSc)
 	sm.activeScene = newSc
 
 	// To animate the scene on-to the stage we ask the scene for a
@@ -78,7 +82,7 @@ func (sm *SceneManager) Step(dt float32) bool {
 		complete := sm.transitionOut.Step(dt)
 		if complete {
 			sm.transitionOut = nil
-			// Pull scene from stack
+			sm.exitOutgoing()
 		}
 	}
 
@@ -87,3 +91,14 @@ func (sm *SceneManager) Step(dt float32) bool {
 
 	return true
 }
+
+// exitOutgoing kills the outgoing scene and releases its resources.
+func (sm *SceneManager) exitOutgoing() {
+	if sm.outgoingScene == nil {
+		return
+	}
+
+	sm.outgoingScene.SetAlive(false)
+	sm.outgoingScene.Release()
+	sm.outgoingScene = nil
+}
@@ -0,0 +1,132 @@
+package components
+
+import (
+	"github.com/wdevore/ranger/graphics"
+	"github.com/wdevore/ranger/rendering"
+	"github.com/wdevore/ranger/rendering/atlas"
+	"github.com/wdevore/ranger/rmath"
+)
+
+// SpriteNode is a Node that draws a textured quad. The quad is centered
+// on the Node's position and sized to the source rectangle in texels.
+type SpriteNode struct {
+	Node
+
+	renderer *rendering.SpriteRenderer
+	texture  *rendering.Texture
+
+	// Tint is multiplied with each texel. Default is White.
+	Tint graphics.Colors
+
+	FlipX bool
+	FlipY bool
+
+	// source is the region of the texture drawn (in texels).
+	source    rmath.Rectangle
+	hasSource bool
+
+	// trimOffset re-centers trimmed sprite sheet frames.
+	trimOffset rmath.Vector3
+
+	// The model-view-projection computed during Visit.
+	mvp rmath.Matrix4
+}
+
+// NewSpriteNode creates a visible SpriteNode that draws the whole texture.
+func NewSpriteNode(renderer *rendering.SpriteRenderer, texture *rendering.Texture) *SpriteNode {
+	s := new(SpriteNode)
+	s.initialize()
+	s.renderer = renderer
+	s.texture = texture
+	return s
+}
+
+func (s *SpriteNode) initialize() {
+	s.Node.initialize() // super
+	s.Visible = true
+	s.Tint.SetFromColors(graphics.White)
+}
+
+// SetTexture changes the texture and resets the source rectangle.
+func (s *SpriteNode) SetTexture(texture *rendering.Texture) {
+	s.texture = texture
+	s.hasSource = false
+	s.trimOffset.Set3Components(0.0, 0.0, 0.0)
+}
+
+// Texture returns the current texture.
+func (s *SpriteNode) Texture() *rendering.Texture {
+	return s.texture
+}
+
+// SetSource selects a region of the texture where x,y is the upper left
+// texel and width x height are the region's dimensions.
+func (s *SpriteNode) SetSource(x, y, width, height float32) {
+	s.source.Set(x, y, width, height, false)
+	s.hasSource = true
+	s.trimOffset.Set3Components(0.0, 0.0, 0.0)
+}
+
+// SetFrame draws a named region of a SpriteSheet. Trimmed frames are offset
+// so they stay aligned with their untrimmed original.
+func (s *SpriteNode) SetFrame(frame *atlas.Frame) {
+	s.texture = frame.Texture
+	s.source.SetWithRectangle(&frame.Source)
+	s.hasSource = true
+	s.trimOffset.Set(&frame.TrimOffset)
+}
+
+// ClearSource draws the whole texture.
+func (s *SpriteNode) ClearSource() {
+	s.hasSource = false
+}
+
+// Size returns the dimensions of what is drawn in texels.
+func (s *SpriteNode) Size() (width, height float32) {
+	if s.hasSource {
+		return s.source.Width, s.source.Height
+	}
+
+	if s.texture == nil {
+		return 0.0, 0.0
+	}
+
+	return float32(s.texture.Width), float32(s.texture.Height)
+}
+
+// ---------------------------------------------------------------
+// Node overrides
+// ---------------------------------------------------------------
+
+// Visit computes the model-view-projection and renders. 'modelT' is the
+// parent's accumulated transform.
+func (s *SpriteNode) Visit(dt float32, modelT *rmath.Matrix4) bool {
+	if !s.Visible || s.texture == nil {
+		return false
+	}
+
+	width, height := s.Size()
+
+	var model, size rmath.Matrix4
+	size.SetScale3Comp(width, height, 1.0)
+	// [trim] x [size]
+	size.TranslateBy(&s.trimOffset)
+
+	// [parent] x [node] x [trim] x [size]
+	rmath.Multiply(modelT, s.CalcTransform(), &model)
+	rmath.Multiply(&model, &size, &s.mvp)
+
+	s.Render()
+
+	return true
+}
+
+// Render draws the sprite using the transform computed by Visit.
+func (s *SpriteNode) Render() {
+	var source *rmath.Rectangle
+	if s.hasSource {
+		source = &s.source
+	}
+
+	s.renderer.Draw(s.texture, &s.mvp, &s.Tint, source, s.FlipX, s.FlipY)
+}
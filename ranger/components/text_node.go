@@ -0,0 +1,272 @@
+package components
+
+import (
+	"github.com/wdevore/ranger/fonts"
+	"github.com/wdevore/ranger/graphics"
+	"github.com/wdevore/ranger/rendering"
+	"github.com/wdevore/ranger/rendering/atlas"
+	"github.com/wdevore/ranger/rmath"
+)
+
+// TextNode is a Node that draws a string. The first line's baseline passes
+// through the Node's position and lines are aligned relative to it.
+type TextNode struct {
+	Node
+
+	renderer rendering.TextDrawer
+	font     *rendering.Font
+
+	text string
+	// spans is the parsed markup, nil for plain text.
+	spans   []rendering.MarkupSpan
+	options fonts.LayoutOptions
+	// maxWidth wraps lines, in world units. 0 disables wrapping.
+	maxWidth float32
+	// size is the text height in world units. Zero means the atlas' size.
+	size float32
+
+	// Color of the glyphs. Default is White.
+	Color graphics.Colors
+	// Style adds outlines, glows and shadows to distance field fonts. Nil
+	// uses the renderer's default.
+	Style *rendering.TextStyle
+
+	// Inline icons of markup are frames of a SpriteSheet.
+	iconRenderer *rendering.SpriteRenderer
+	icons        *atlas.SpriteSheet
+
+	layout *fonts.RichLayout
+	mesh   *rendering.TextMesh
+	// dirty means the layout must be redone, which makes the mesh stale.
+	dirty bool
+	// meshStale means the mesh must be rebuilt from the layout.
+	meshStale bool
+
+	// The model-view-projection computed during Visit.
+	mvp rmath.Matrix4
+}
+
+// NewTextNode creates a visible, empty, left aligned TextNode.
+// Use an SDFTextRenderer with a distance field font for text that stays
+// sharp when scaled.
+func NewTextNode(renderer rendering.TextDrawer, font *rendering.Font) *TextNode {
+	t := new(TextNode)
+	t.initialize()
+	t.renderer = renderer
+	t.font = font
+	return t
+}
+
+func (t *TextNode) initialize() {
+	t.Node.initialize() // super
+	t.Visible = true
+	t.Color.SetFromColors(graphics.White)
+	t.mesh = rendering.NewTextMesh()
+}
+
+// SetText changes the string. Lines are separated by '\n'.
+func (t *TextNode) SetText(text string) {
+	if t.text != text || t.spans != nil {
+		t.text = text
+		t.spans = nil
+		t.dirty = true
+	}
+}
+
+// SetMarkup changes the string to rich text, see rendering.ParseMarkup.
+// Icons are drawn once SetIcons is called. The text is unchanged if the
+// markup is invalid.
+func (t *TextNode) SetMarkup(markup string) error {
+	spans, err := rendering.ParseMarkup(markup)
+	if err != nil {
+		return err
+	}
+
+	t.text = markup
+	t.spans = spans
+	t.dirty = true
+
+	return nil
+}
+
+// SetIcons sets the SpriteSheet that markup icons are frames of.
+func (t *TextNode) SetIcons(renderer *rendering.SpriteRenderer, icons *atlas.SpriteSheet) {
+	t.iconRenderer = renderer
+	t.icons = icons
+	t.dirty = true
+}
+
+// Text returns the current string or markup.
+func (t *TextNode) Text() string {
+	return t.text
+}
+
+// SetAlignment changes how lines are aligned to the Node's position.
+func (t *TextNode) SetAlignment(align fonts.Alignment) {
+	if t.options.Align != align {
+		t.options.Align = align
+		t.dirty = true
+	}
+}
+
+// SetMaxWidth wraps lines wider than width (world units). 0 disables
+// wrapping.
+func (t *TextNode) SetMaxWidth(width float32) {
+	if t.maxWidth != width {
+		t.maxWidth = width
+		t.dirty = true
+	}
+}
+
+// SetLineSpacing multiplies the font's line height.
+func (t *TextNode) SetLineSpacing(spacing float32) {
+	if t.options.LineSpacing != spacing {
+		t.options.LineSpacing = spacing
+		t.dirty = true
+	}
+}
+
+// Bounds returns the text's extent in the Node's local space (world units
+// before the Node's transform) with +Y upwards.
+func (t *TextNode) Bounds() rmath.Rectangle {
+	t.relayout()
+
+	b := t.layout.Bounds
+	s := t.fontScale()
+	b.SetByComp(b.Left*s, b.Top*s, b.Right*s, b.Bottom*s)
+
+	return b
+}
+
+// SetSize sets the text height in world units.
+func (t *TextNode) SetSize(size float32) {
+	if t.size != size {
+		t.size = size
+		// Wrapping depends on the scale
+		t.dirty = true
+	}
+}
+
+// SetFont changes the font.
+func (t *TextNode) SetFont(font *rendering.Font) {
+	t.font = font
+	t.dirty = true
+}
+
+// fontScale maps atlas pixels to world units. Without a size the atlas'
+// DefaultScale is used.
+func (t *TextNode) fontScale() float32 {
+	if t.size <= 0.0 || t.font.Atlas.Size <= 0.0 {
+		if t.font.Atlas.DefaultScale > 0.0 {
+			return t.font.Atlas.DefaultScale
+		}
+		return 1.0
+	}
+	return t.size / t.font.Atlas.Size
+}
+
+// relayout lays out the text if it changed since the last layout.
+func (t *TextNode) relayout() {
+	if t.layout != nil && !t.dirty {
+		return
+	}
+
+	t.dirty = false
+	t.meshStale = true
+
+	options := t.options
+	// The layout is in atlas pixels
+	options.MaxWidth = t.maxWidth / t.fontScale()
+
+	if t.spans == nil {
+		t.layout = fonts.LayoutRuns(t.font.Atlas, []fonts.Run{{Text: t.text}}, nil, options)
+		return
+	}
+
+	var icons fonts.IconMetrics
+	if t.icons != nil {
+		icons = t.icons
+	}
+
+	t.layout = fonts.LayoutRuns(t.font.Atlas, rendering.MarkupRuns(t.spans), icons, options)
+}
+
+// ---------------------------------------------------------------
+// Node overrides
+// ---------------------------------------------------------------
+
+// Visit computes the model-view-projection and renders. 'modelT' is the
+// parent's accumulated transform.
+func (t *TextNode) Visit(dt float32, modelT *rmath.Matrix4) bool {
+	if !t.Visible || t.font == nil || t.text == "" {
+		return false
+	}
+
+	t.relayout()
+
+	if t.meshStale {
+		t.mesh.TagColors = rendering.MarkupColors(t.spans)
+		t.mesh.Build(t.font.Atlas.RichQuads(t.layout.Glyphs))
+		t.meshStale = false
+	}
+
+	scale := t.fontScale()
+
+	var model, size rmath.Matrix4
+	size.SetScale3Comp(scale, scale, 1.0)
+
+	// [parent] x [node] x [size]
+	rmath.Multiply(modelT, t.CalcTransform(), &model)
+	rmath.Multiply(&model, &size, &t.mvp)
+
+	t.Render()
+
+	return true
+}
+
+// Delete deletes the text mesh's GL objects. The next Visit builds them
+// again.
+func (t *TextNode) Delete() {
+	t.mesh.Delete()
+	t.meshStale = true
+}
+
+// Render draws the text using the transform computed by Visit.
+func (t *TextNode) Render() {
+	t.renderer.Draw(t.mesh, t.font, &t.mvp, &t.Color, t.Style)
+
+	if t.icons != nil && t.iconRenderer != nil {
+		t.renderIcons()
+	}
+}
+
+// renderIcons draws markup icons as sprites, untinted except for the
+// span's colour and the text's alpha.
+func (t *TextNode) renderIcons() {
+	var quad, mvp rmath.Matrix4
+	var tint graphics.Colors
+
+	for _, g := range t.layout.Glyphs {
+		if g.Icon == "" {
+			continue
+		}
+
+		frame := t.icons.Frame(g.Icon)
+		if frame == nil {
+			continue
+		}
+
+		tint.SetFromColors(graphics.White)
+		if span := t.spans[g.Tag]; span.Color != nil {
+			tint.SetFromColors(span.Color)
+		}
+		tint.A *= t.Color.A
+
+		// The unit quad is centered so move it to the icon's center.
+		quad.SetTranslate3Comp(g.X+g.Width/2.0, g.Y+g.Height/2.0, 0.0)
+		quad.PostScale(g.Width, g.Height, 1.0)
+
+		rmath.Multiply(&t.mvp, &quad, &mvp)
+		t.iconRenderer.Draw(frame.Texture, &mvp, &tint, &frame.Source, false, false)
+	}
+}
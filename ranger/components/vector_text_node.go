@@ -0,0 +1,179 @@
+package components
+
+import (
+	"github.com/wdevore/ranger/fonts"
+	"github.com/wdevore/ranger/graphics"
+	"github.com/wdevore/ranger/rendering"
+	"github.com/wdevore/ranger/rendering/atlas"
+	"github.com/wdevore/ranger/rmath"
+)
+
+// VectorTextNode is a Node that draws a string using the glyph shapes of a
+// VectorFontAtlas (filled outlines) or StrokeFontAtlas (lines). Glyphs are
+// vectors so they stay crisp at any scale or rotation. The first line's
+// baseline passes through the Node's position.
+type VectorTextNode struct {
+	Node
+
+	renderer rendering.VectorShapeDrawer
+	font     atlas.ShapeFont
+
+	text    string
+	options fonts.LayoutOptions
+	// maxWidth wraps lines, in world units. 0 disables wrapping.
+	maxWidth float32
+	// size is the text height (em) in world units.
+	size float32
+
+	// Color of the glyphs. Default is White.
+	Color graphics.Colors
+
+	layout *fonts.TextLayout
+	// dirty means the layout must be redone.
+	dirty bool
+
+	// The text's transform computed during Visit and each glyph's
+	// model-view-projection.
+	textT rmath.Matrix4
+	mvp   rmath.Matrix4
+}
+
+// NewVectorTextNode creates a visible, empty, left aligned VectorTextNode
+// that is 1 unit high. 'renderer' is typically a VectorShapeRenderer, a
+// VectorBatchRenderer so each glyph isn't a draw call, or a RenderQueue.
+func NewVectorTextNode(renderer rendering.VectorShapeDrawer, font atlas.ShapeFont) *VectorTextNode {
+	t := new(VectorTextNode)
+	t.initialize()
+	t.renderer = renderer
+	t.font = font
+	return t
+}
+
+func (t *VectorTextNode) initialize() {
+	t.Node.initialize() // super
+	t.Visible = true
+	t.size = 1.0
+	t.Color.SetFromColors(graphics.White)
+}
+
+// SetText changes the string. Lines are separated by '\n'.
+func (t *VectorTextNode) SetText(text string) {
+	if t.text != text {
+		t.text = text
+		t.dirty = true
+	}
+}
+
+// Text returns the current string.
+func (t *VectorTextNode) Text() string {
+	return t.text
+}
+
+// SetAlignment changes how lines are aligned to the Node's position.
+func (t *VectorTextNode) SetAlignment(align fonts.Alignment) {
+	if t.options.Align != align {
+		t.options.Align = align
+		t.dirty = true
+	}
+}
+
+// SetMaxWidth wraps lines wider than width (world units). 0 disables
+// wrapping.
+func (t *VectorTextNode) SetMaxWidth(width float32) {
+	if t.maxWidth != width {
+		t.maxWidth = width
+		t.dirty = true
+	}
+}
+
+// SetLineSpacing multiplies the font's line height.
+func (t *VectorTextNode) SetLineSpacing(spacing float32) {
+	if t.options.LineSpacing != spacing {
+		t.options.LineSpacing = spacing
+		t.dirty = true
+	}
+}
+
+// Bounds returns the text's extent in the Node's local space (world units
+// before the Node's transform) with +Y upwards.
+func (t *VectorTextNode) Bounds() rmath.Rectangle {
+	t.relayout()
+
+	b := t.layout.Bounds
+	s := t.fontScale()
+	b.SetByComp(b.Left*s, b.Top*s, b.Right*s, b.Bottom*s)
+
+	return b
+}
+
+// SetSize sets the text height (em) in world units.
+func (t *VectorTextNode) SetSize(size float32) {
+	if t.size != size {
+		t.size = size
+		// Wrapping depends on the scale
+		t.dirty = true
+	}
+}
+
+func (t *VectorTextNode) fontScale() float32 {
+	return t.size
+}
+
+// relayout lays out the text if it changed since the last layout.
+func (t *VectorTextNode) relayout() {
+	if t.layout != nil && !t.dirty {
+		return
+	}
+
+	options := t.options
+	// The layout is in ems
+	if t.size > 0.0 {
+		options.MaxWidth = t.maxWidth / t.size
+	}
+
+	t.layout = t.font.Layout(t.text, options)
+	t.dirty = false
+}
+
+// ---------------------------------------------------------------
+// Node overrides
+// ---------------------------------------------------------------
+
+// Visit renders each glyph. 'modelT' is the parent's accumulated transform.
+func (t *VectorTextNode) Visit(dt float32, modelT *rmath.Matrix4) bool {
+	if !t.Visible || t.text == "" {
+		return false
+	}
+
+	t.relayout()
+
+	var model, size rmath.Matrix4
+	size.SetScale3Comp(t.size, t.size, 1.0)
+
+	// [parent] x [node] x [size]
+	rmath.Multiply(modelT, t.CalcTransform(), &model)
+	rmath.Multiply(&model, &size, &t.textT)
+
+	t.Render()
+
+	return true
+}
+
+// Render draws the glyphs using the transform computed by Visit.
+func (t *VectorTextNode) Render() {
+	var pen rmath.Matrix4
+	vo := t.font.Object()
+
+	for _, p := range t.layout.Glyphs {
+		shape := t.font.Glyph(p.Rune)
+		if shape == nil {
+			continue
+		}
+
+		// [text] x [pen]
+		pen.SetTranslate3Comp(p.X, p.Y, 0.0)
+		rmath.Multiply(&t.textT, &pen, &t.mvp)
+
+		t.renderer.Draw(vo, shape, &t.mvp, &t.Color)
+	}
+}
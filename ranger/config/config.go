@@ -21,6 +21,9 @@ type EngineObj struct {
 	GLMajorVersion   int
 	GLMinorVersion   int
 	FPSRefreshRate   float32
+	// ShaderHotReload recompiles shaders when their files change. For
+	// development only.
+	ShaderHotReload bool
 }
 
 // WindowObj settings
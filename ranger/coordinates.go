@@ -0,0 +1,108 @@
+package ranger
+
+import (
+	"github.com/wdevore/ranger/rmath"
+)
+
+// Coordinate spaces:
+//   Device:  window pixels with an upper left origin, as reported by GLFW.
+//   Virtual: the Camera's projection space in VirtualRes units. If the
+//            Camera is centered the origin is the center of the view.
+//   World:   the space the scene graph lives in. The View (and any
+//            CameraController) maps World into Virtual.
+
+// ----------------------------------------------------------------------------
+// RenderView conversions
+// ----------------------------------------------------------------------------
+
+// DeviceToVirtual maps device coordinates into virtual coordinates.
+func (rv *RenderView) DeviceToVirtual(x, y float32, out *rmath.Vector3) {
+	rv.Viewport.DeviceToNDC(x, y, rv.windowHeight, out)
+	unproject(&rv.Camera.Matrix, out)
+}
+
+// VirtualToWorld maps virtual coordinates into world coordinates.
+func (rv *RenderView) VirtualToWorld(virtual, out *rmath.Vector3) {
+	out.Set(virtual)
+	unproject(&rv.View.Matrix, out)
+}
+
+// DeviceToWorld maps device coordinates into world coordinates, for
+// example, to pick Nodes under the mouse.
+func (rv *RenderView) DeviceToWorld(x, y float32, out *rmath.Vector3) {
+	var viewProjection rmath.Matrix4
+	rmath.Multiply(&rv.Camera.Matrix, &rv.View.Matrix, &viewProjection)
+
+	rv.Viewport.DeviceToNDC(x, y, rv.windowHeight, out)
+	unproject(&viewProjection, out)
+}
+
+// WorldToVirtual maps world coordinates into virtual coordinates.
+func (rv *RenderView) WorldToVirtual(world, out *rmath.Vector3) {
+	out.Set(world)
+	out.Mul(&rv.View.Matrix)
+}
+
+// WorldToScreen maps world coordinates into device coordinates.
+func (rv *RenderView) WorldToScreen(world, out *rmath.Vector3) {
+	var viewProjection rmath.Matrix4
+	rmath.Multiply(&rv.Camera.Matrix, &rv.View.Matrix, &viewProjection)
+
+	ndc := rmath.NewVector3()
+	ndc.Set(world)
+	ndc.Mul(&viewProjection)
+
+	rv.Viewport.NDCToDevice(ndc, rv.windowHeight, out)
+}
+
+// ContainsDevice checks if device coordinates are within this view's Viewport.
+func (rv *RenderView) ContainsDevice(x, y float32) bool {
+	return rv.Viewport.ContainsDevice(x, y, rv.windowHeight)
+}
+
+// unproject maps 'v' through the inverse of 'm'.
+func unproject(m *rmath.Matrix4, v *rmath.Vector3) {
+	var inv rmath.Matrix4
+	if !inv.Invert(m) {
+		return
+	}
+
+	v.Mul(&inv)
+}
+
+// ----------------------------------------------------------------------------
+// Engine conversions against the primary view
+// ----------------------------------------------------------------------------
+
+// DeviceToVirtual maps device coordinates into the primary view's virtual coordinates.
+func (e *Engine) DeviceToVirtual(x, y float32, out *rmath.Vector3) {
+	e.PrimaryView().DeviceToVirtual(x, y, out)
+}
+
+// VirtualToWorld maps the primary view's virtual coordinates into world coordinates.
+func (e *Engine) VirtualToWorld(virtual, out *rmath.Vector3) {
+	e.PrimaryView().VirtualToWorld(virtual, out)
+}
+
+// DeviceToWorld maps device coordinates into the primary view's world coordinates.
+func (e *Engine) DeviceToWorld(x, y float32, out *rmath.Vector3) {
+	e.PrimaryView().DeviceToWorld(x, y, out)
+}
+
+// WorldToScreen maps world coordinates into device coordinates through the primary view.
+func (e *Engine) WorldToScreen(world, out *rmath.Vector3) {
+	e.PrimaryView().WorldToScreen(world, out)
+}
+
+// RenderViewAt returns the top most visible view containing the device
+// coordinates, or nil. Useful for split-screen input.
+func (e *Engine) RenderViewAt(x, y float32) *RenderView {
+	for i := len(e.views) - 1; i >= 0; i-- {
+		rv := e.views[i]
+		if rv.Visible && rv.ContainsDevice(x, y) {
+			return rv
+		}
+	}
+
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/go-gl/glfw/v3.2/glfw"
 	"github.com/wdevore/ranger/config"
 	"github.com/wdevore/ranger/graphics"
+	"github.com/wdevore/ranger/rendering"
 	"github.com/wdevore/ranger/window"
 )
 
@@ -48,8 +49,19 @@ type Engine struct {
 	Camera   graphics.Camera
 	View     graphics.View
 
+	// CameraController is optional. If present it updates the View each
+	// frame as the primary view's Controller.
+	CameraController *graphics.CameraController
+
 	renderContext graphics.RenderContext
 
+	// shaderWatcher is nil unless ShaderHotReload is enabled.
+	shaderWatcher *rendering.ShaderWatcher
+
+	// Views are rendered in sequence each frame. The first view is the
+	// primary view built from Viewport, Camera and View above.
+	views []*RenderView
+
 	// ---------------------------------------------------------------------
 	// Stage and Scene
 	// ---------------------------------------------------------------------
@@ -62,6 +74,15 @@ func NewEngine(gs GameShell) *Engine {
 	e := new(Engine)
 	e.game = gs
 
+	primary := new(RenderView)
+	primary.Name = "Primary"
+	primary.Viewport = &e.Viewport
+	primary.Camera = &e.Camera
+	primary.View = &e.View
+	primary.Visible = true
+
+	e.views = append(e.views, primary)
+
 	return e
 }
 
@@ -71,6 +92,10 @@ func (e *Engine) Launch() error {
 
 	e.loadConfig()
 
+	if e.config.Engine.ShaderHotReload {
+		e.shaderWatcher = rendering.NewShaderWatcher()
+	}
+
 	// Now notify developer that they can configure their game.
 	configured := e.game.Configure(e)
 
@@ -155,6 +180,12 @@ func (e *Engine) start() error {
 // Stop performs any last minute resource cleanups
 func (e *Engine) Stop() {
 	println("Engine stopping...")
+
+	// Anything still alive wasn't deleted by its owner.
+	if report := graphics.Resources.Report(); report != "" {
+		println(report)
+	}
+
 	println("Engine stopped")
 
 	if e.engineError == nil {
@@ -167,52 +198,154 @@ func (e *Engine) loop() {
 		e.rWindow.Poll()
 
 		// ---------------- Update BEGIN -----------------------------
-		e.currentUpdateTime = glfw.GetTime()
+		now := glfw.GetTime()
+		if e.currentUpdateTime > 0.0 {
+			e.deltaTime = now - e.currentUpdateTime
+		}
+		e.currentUpdateTime = now
+
+		dt := float32(e.deltaTime)
+
+		if e.shaderWatcher != nil {
+			e.shaderWatcher.Update(dt)
+		}
+
+		if e.CameraController != nil {
+			e.PrimaryView().Controller = e.CameraController
+		}
+
+		for _, rv := range e.views {
+			rv.Update(dt)
+		}
+
 		e.deltaUpdateTime = glfw.GetTime() - e.currentUpdateTime
 		// ---------------- Update END -----------------------------
 
 		// This clear sync locked with the vertical refresh. The clear itself
 		// takes ~30 microseconds on a mid-range mobile nvidia GPU.
+		e.Viewport.DisableScissor()
 		e.renderContext.Clear()
 
+		// ---------------- Render BEGIN -----------------------------
+		for _, rv := range e.views {
+			rv.Render(dt, &e.renderContext)
+		}
+
+		e.Viewport.DisableScissor()
+		// ---------------- Render END -----------------------------
+
 		e.rWindow.Swap()
+
+		graphics.GLState.EndFrame()
 	}
 }
 
+// StateStats returns the GL state changes made, and skipped, during the
+// last frame.
+func (e *Engine) StateStats() graphics.StateStats {
+	return graphics.GLState.LastFrame
+}
+
+// WatchShader reloads a loaded shader when its files change, if
+// ShaderHotReload is enabled in the config. Otherwise it does nothing.
+func (e *Engine) WatchShader(shader *rendering.Shader) {
+	if e.shaderWatcher != nil {
+		e.shaderWatcher.Watch(shader)
+	}
+}
+
+// ShaderWatcher returns the watcher used when ShaderHotReload is enabled,
+// otherwise nil. Its LastError can be shown on screen.
+func (e *Engine) ShaderWatcher() *rendering.ShaderWatcher {
+	return e.shaderWatcher
+}
+
 func (e *Engine) configureStage(config *config.Settings) {
-	e.Viewport.SetDimensions(0, 0, config.Window.DeviceRes.Width, config.Window.DeviceRes.Height)
+	// The primary view covers the whole device unless the game configured
+	// it otherwise, for example, the left half for split-screen.
+	if e.Viewport.Width() == 0 {
+		e.ConfigureRenderView(e.PrimaryView(), 0, 0, config.Window.DeviceRes.Width, config.Window.DeviceRes.Height)
+	}
 	e.Viewport.Apply()
 
-	// Calc the aspect ratio between the physical (aka device) dimensions and the
-	// the virtual (aka user's design choice) dimensions.
+	// -----------------------------------------------------------------
+	// Create stage
+	// -----------------------------------------------------------------
+
+}
 
-	deviceRatio := float64(config.Window.DeviceRes.Width) / float64(config.Window.DeviceRes.Height)
-	virtualRatio := float64(config.Window.VirtualRes.Width) / float64(config.Window.VirtualRes.Height)
+// ratioCorrection calculates the aspect ratio correction between the physical
+// (aka device) dimensions and the virtual (aka user's design choice) dimensions.
+func (e *Engine) ratioCorrection() float64 {
+	window := &e.config.Window
 
-	xRatioCorrection := float64(config.Window.DeviceRes.Width) / float64(config.Window.VirtualRes.Width)
-	yRatioCorrection := float64(config.Window.DeviceRes.Height) / float64(config.Window.VirtualRes.Height)
+	deviceRatio := float64(window.DeviceRes.Width) / float64(window.DeviceRes.Height)
+	virtualRatio := float64(window.VirtualRes.Width) / float64(window.VirtualRes.Height)
 
-	var ratioCorrection float64
+	xRatioCorrection := float64(window.DeviceRes.Width) / float64(window.VirtualRes.Width)
+	yRatioCorrection := float64(window.DeviceRes.Height) / float64(window.VirtualRes.Height)
 
 	if virtualRatio < deviceRatio {
-		ratioCorrection = yRatioCorrection
-	} else {
-		ratioCorrection = xRatioCorrection
+		return yRatioCorrection
 	}
 
-	e.Camera.SetProjection(
-		float32(ratioCorrection),
-		0.0, 0.0,
-		float32(config.Window.DeviceRes.Height), float32(config.Window.DeviceRes.Width))
+	return xRatioCorrection
+}
 
-	if config.Camera.Centered {
-		e.Camera.Centered()
+// ----------------------------------------------------------------------------
+// Render views
+// ----------------------------------------------------------------------------
+
+// PrimaryView returns the view built from the Engine's Viewport, Camera and View.
+func (e *Engine) PrimaryView() *RenderView {
+	return e.views[0]
+}
+
+// RenderViews returns all views in render order.
+func (e *Engine) RenderViews() []*RenderView {
+	return e.views
+}
+
+// AddRenderView creates a view covering the device region x,y,width,height
+// (lower left origin) and appends it to the render order. It can be called
+// from Configure.
+func (e *Engine) AddRenderView(name string, x, y, width, height int) *RenderView {
+	rv := NewRenderView(name)
+
+	e.ConfigureRenderView(rv, x, y, width, height)
+
+	e.views = append(e.views, rv)
+
+	return rv
+}
+
+// RemoveRenderView removes a view from the render order. The primary
+// view can't be removed, hide it instead.
+func (e *Engine) RemoveRenderView(rv *RenderView) {
+	for i := 1; i < len(e.views); i++ {
+		if e.views[i] == rv {
+			e.views = append(e.views[:i], e.views[i+1:]...)
+			return
+		}
 	}
+}
 
-	e.View.SetProjection(config.Camera.View.X, config.Camera.View.Y, config.Camera.View.Z)
+// ConfigureRenderView sets a view's Viewport to the device region x,y,width,height
+// and sets its Camera and View projections from the configuration settings.
+func (e *Engine) ConfigureRenderView(rv *RenderView, x, y, width, height int) {
+	config := &e.config
 
-	// -----------------------------------------------------------------
-	// Create stage
-	// -----------------------------------------------------------------
+	rv.Viewport.SetDimensions(x, y, width, height)
+	rv.windowHeight = config.Window.DeviceRes.Height
+
+	rv.Camera.SetProjection(
+		float32(e.ratioCorrection()),
+		0.0, 0.0,
+		float32(height), float32(width))
+
+	if config.Camera.Centered {
+		rv.Camera.Centered()
+	}
 
+	rv.View.SetProjection(config.Camera.View.X, config.Camera.View.Y, config.Camera.View.Z)
 }
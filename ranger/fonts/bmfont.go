@@ -0,0 +1,604 @@
+package fonts
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	// Page formats
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// BMFont is an AngelCode bitmap font descriptor (.fnt).
+// See http://www.angelcode.com/products/bmfont/doc/file_format.html
+type BMFont struct {
+	Face string
+	// Size is negative when the font was generated to match char height.
+	Size int
+
+	LineHeight int
+	// Base is the distance from the top of a line to the baseline.
+	Base int
+
+	ScaleW, ScaleH int
+
+	// Pages are the page image file names indexed by page id.
+	Pages []string
+
+	Chars    []BMChar
+	Kernings []BMKerning
+
+	// DistanceRange is non-zero for distance field fonts. It is read from
+	// the "distanceField" line written by msdf-bmfont and sdfgen.
+	DistanceRange int
+}
+
+// BMChar is a glyph's page region and metrics in texels.
+type BMChar struct {
+	ID            rune
+	X, Y          int
+	Width, Height int
+	XOffset       int
+	YOffset       int
+	XAdvance      int
+	Page          int
+	Channel       int
+}
+
+// BMKerning adjusts the advance between two runes.
+type BMKerning struct {
+	First, Second rune
+	Amount        int
+}
+
+// LoadBMFont reads a text or binary .fnt and its pages, which are
+// relative to the .fnt file.
+func LoadBMFont(path string) (*GlyphAtlas, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	bf, err := ParseBMFont(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	pages := []image.Image{}
+	for _, name := range bf.Pages {
+		img, err := loadPage(filepath.Join(filepath.Dir(path), name))
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, img)
+	}
+
+	return bf.Atlas(pages)
+}
+
+func loadPage(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	return img, nil
+}
+
+// ParseBMFont parses either the text or binary .fnt format.
+func ParseBMFont(data []byte) (*BMFont, error) {
+	if bytes.HasPrefix(data, []byte("BMF")) {
+		return parseBinaryBMFont(data)
+	}
+
+	return parseTextBMFont(data)
+}
+
+// BMChar channels, a char is in one channel of a packed page or in all.
+const (
+	channelBlue  = 1
+	channelGreen = 2
+	channelRed   = 4
+	channelAlpha = 8
+	channelAll   = 15
+)
+
+// Atlas converts the descriptor and its page images into a GlyphAtlas.
+// Atlas pages are white with the glyph coverage in alpha, which is what
+// the text shaders sample. Coverage comes from the channel a char names,
+// or for chars in all channels from the page's alpha, or its luminance if
+// the page is opaque, for example, an 8-bit grayscale page. Chars packed
+// into single channels get a page per channel.
+func (bf *BMFont) Atlas(pages []image.Image) (*GlyphAtlas, error) {
+	if len(pages) != len(bf.Pages) {
+		return nil, fmt.Errorf("expected %d pages, got: %d", len(bf.Pages), len(pages))
+	}
+
+	size := bf.Size
+	if size < 0 {
+		size = -size
+	}
+
+	ga := NewGlyphAtlas(bf.Face, float32(size))
+	ga.LineHeight = float32(bf.LineHeight)
+	ga.Ascent = float32(bf.Base)
+	ga.Descent = float32(bf.LineHeight - bf.Base)
+	ga.DistanceRange = float32(bf.DistanceRange)
+
+	// Atlas page indices by source page and channel. All channel pages
+	// keep the source page's index.
+	converted := map[[2]int]int{}
+
+	for i, page := range pages {
+		ga.Pages = append(ga.Pages, coveragePage(page, channelAll))
+		converted[[2]int{i, channelAll}] = i
+	}
+
+	for _, c := range bf.Chars {
+		if c.Page < 0 || c.Page >= len(pages) {
+			return nil, fmt.Errorf("char %d refers to missing page %d", c.ID, c.Page)
+		}
+
+		channel := c.Channel
+		switch channel {
+		case channelBlue, channelGreen, channelRed, channelAlpha:
+		default:
+			channel = channelAll
+		}
+
+		key := [2]int{c.Page, channel}
+		page, ok := converted[key]
+		if !ok {
+			page = len(ga.Pages)
+			ga.Pages = append(ga.Pages, coveragePage(pages[c.Page], channel))
+			converted[key] = page
+		}
+
+		ga.Glyphs[c.ID] = &Glyph{
+			Rune:     c.ID,
+			Page:     page,
+			Region:   image.Rect(c.X, c.Y, c.X+c.Width, c.Y+c.Height),
+			BearingX: float32(c.XOffset),
+			BearingY: float32(bf.Base - c.YOffset),
+			Advance:  float32(c.XAdvance),
+		}
+	}
+
+	for _, k := range bf.Kernings {
+		ga.SetKern(k.First, k.Second, float32(k.Amount))
+	}
+
+	return ga, nil
+}
+
+// coveragePage converts a page to NRGBA with the coverage of a channel in
+// alpha. Pages with alpha are kept as they are for channelAll.
+func coveragePage(page image.Image, channel int) *image.NRGBA {
+	b := page.Bounds()
+
+	opaque := false
+	if o, ok := page.(interface{ Opaque() bool }); ok {
+		opaque = o.Opaque()
+	}
+
+	if channel == channelAll && !opaque {
+		nrgba, ok := page.(*image.NRGBA)
+		if ok && b.Min == (image.Point{}) {
+			return nrgba
+		}
+		nrgba = image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(nrgba, nrgba.Bounds(), page, b.Min, draw.Src)
+		return nrgba
+	}
+
+	nrgba := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := page.At(x, y)
+
+			var coverage uint8
+			switch channel {
+			case channelAll:
+				coverage = color.GrayModel.Convert(c).(color.Gray).Y
+			default:
+				n := color.NRGBAModel.Convert(c).(color.NRGBA)
+				switch channel {
+				case channelBlue:
+					coverage = n.B
+				case channelGreen:
+					coverage = n.G
+				case channelRed:
+					coverage = n.R
+				default:
+					coverage = n.A
+				}
+			}
+
+			i := nrgba.PixOffset(x-b.Min.X, y-b.Min.Y)
+			nrgba.Pix[i] = 255
+			nrgba.Pix[i+1] = 255
+			nrgba.Pix[i+2] = 255
+			nrgba.Pix[i+3] = coverage
+		}
+	}
+
+	return nrgba
+}
+
+// ---------------------------------------------------------------------
+// Text format
+// ---------------------------------------------------------------------
+
+// parseTextBMFont parses lines of a tag followed by key=value pairs, for
+// example:
+//   char id=65 x=10 y=0 width=12 height=14 xoffset=0 yoffset=4 xadvance=13 page=0 chnl=15
+func parseTextBMFont(data []byte) (*BMFont, error) {
+	bf := new(BMFont)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+
+		tag, attrs, err := parseBMLine(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", lineNumber, err)
+		}
+
+		switch tag {
+		case "info":
+			bf.Face = attrs.text("face")
+			bf.Size = attrs.number("size")
+		case "common":
+			bf.LineHeight = attrs.number("lineHeight")
+			bf.Base = attrs.number("base")
+			bf.ScaleW = attrs.number("scaleW")
+			bf.ScaleH = attrs.number("scaleH")
+			bf.Pages = make([]string, attrs.number("pages"))
+		case "page":
+			id := attrs.number("id")
+			if id < 0 || id >= len(bf.Pages) {
+				return nil, fmt.Errorf("line %d: page id %d out of range", lineNumber, id)
+			}
+			bf.Pages[id] = attrs.text("file")
+		case "char":
+			bf.Chars = append(bf.Chars, BMChar{
+				ID:       rune(attrs.number("id")),
+				X:        attrs.number("x"),
+				Y:        attrs.number("y"),
+				Width:    attrs.number("width"),
+				Height:   attrs.number("height"),
+				XOffset:  attrs.number("xoffset"),
+				YOffset:  attrs.number("yoffset"),
+				XAdvance: attrs.number("xadvance"),
+				Page:     attrs.number("page"),
+				Channel:  attrs.number("chnl"),
+			})
+		case "distanceField":
+			bf.DistanceRange = attrs.number("distanceRange")
+		case "kerning":
+			bf.Kernings = append(bf.Kernings, BMKerning{
+				First:  rune(attrs.number("first")),
+				Second: rune(attrs.number("second")),
+				Amount: attrs.number("amount"),
+			})
+		}
+
+		if attrs.err != nil {
+			return nil, fmt.Errorf("line %d: %s", lineNumber, attrs.err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if bf.LineHeight == 0 {
+		return nil, fmt.Errorf("missing 'common' line")
+	}
+
+	return bf, nil
+}
+
+// NewBMFont describes a GlyphAtlas whose pages are saved as 'pageNames'.
+// Metrics are rounded to whole pixels.
+func NewBMFont(ga *GlyphAtlas, pageNames []string) *BMFont {
+	bf := new(BMFont)
+	bf.Face = ga.Name
+	bf.Size = int(ga.Size + 0.5)
+	bf.LineHeight = round(ga.LineHeight)
+	bf.Base = round(ga.Ascent)
+	bf.Pages = pageNames
+	bf.DistanceRange = round(ga.DistanceRange)
+
+	if len(ga.Pages) > 0 {
+		b := ga.Pages[0].Bounds()
+		bf.ScaleW = b.Dx()
+		bf.ScaleH = b.Dy()
+	}
+
+	runes := []rune{}
+	for r := range ga.Glyphs {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+
+	for _, r := range runes {
+		g := ga.Glyphs[r]
+		bf.Chars = append(bf.Chars, BMChar{
+			ID:       r,
+			X:        g.Region.Min.X,
+			Y:        g.Region.Min.Y,
+			Width:    g.Region.Dx(),
+			Height:   g.Region.Dy(),
+			XOffset:  round(g.BearingX),
+			YOffset:  bf.Base - round(g.BearingY),
+			XAdvance: round(g.Advance),
+			Page:     g.Page,
+			Channel:  15,
+		})
+	}
+
+	if ga.kernSource != nil {
+		// Pairs are looked up on demand so every pair must be asked for.
+		for _, first := range runes {
+			for _, second := range runes {
+				if k := round(ga.Kern(first, second)); k != 0 {
+					bf.Kernings = append(bf.Kernings, BMKerning{First: first, Second: second, Amount: k})
+				}
+			}
+		}
+	} else {
+		for pair, amount := range ga.kerning {
+			if k := round(amount); k != 0 {
+				bf.Kernings = append(bf.Kernings, BMKerning{First: pair[0], Second: pair[1], Amount: k})
+			}
+		}
+	}
+	sort.Slice(bf.Kernings, func(i, j int) bool {
+		a, b := bf.Kernings[i], bf.Kernings[j]
+		return a.First < b.First || (a.First == b.First && a.Second < b.Second)
+	})
+
+	return bf
+}
+
+// WriteText writes the text format.
+func (bf *BMFont) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "info face=\"%s\" size=%d unicode=1\n", bf.Face, bf.Size)
+	fmt.Fprintf(bw, "common lineHeight=%d base=%d scaleW=%d scaleH=%d pages=%d packed=0\n",
+		bf.LineHeight, bf.Base, bf.ScaleW, bf.ScaleH, len(bf.Pages))
+
+	for id, name := range bf.Pages {
+		fmt.Fprintf(bw, "page id=%d file=\"%s\"\n", id, name)
+	}
+
+	if bf.DistanceRange != 0 {
+		fmt.Fprintf(bw, "distanceField fieldType=sdf distanceRange=%d\n", bf.DistanceRange)
+	}
+
+	fmt.Fprintf(bw, "chars count=%d\n", len(bf.Chars))
+	for _, c := range bf.Chars {
+		fmt.Fprintf(bw, "char id=%d x=%d y=%d width=%d height=%d xoffset=%d yoffset=%d xadvance=%d page=%d chnl=%d\n",
+			c.ID, c.X, c.Y, c.Width, c.Height, c.XOffset, c.YOffset, c.XAdvance, c.Page, c.Channel)
+	}
+
+	fmt.Fprintf(bw, "kernings count=%d\n", len(bf.Kernings))
+	for _, k := range bf.Kernings {
+		fmt.Fprintf(bw, "kerning first=%d second=%d amount=%d\n", k.First, k.Second, k.Amount)
+	}
+
+	return bw.Flush()
+}
+
+func round(v float32) int {
+	return int(math.Floor(float64(v) + 0.5))
+}
+
+// bmAttrs are a text line's key=value pairs. The first conversion error
+// is kept.
+type bmAttrs struct {
+	values map[string]string
+	err    error
+}
+
+func (a *bmAttrs) text(key string) string {
+	return a.values[key]
+}
+
+func (a *bmAttrs) number(key string) int {
+	v, ok := a.values[key]
+	if !ok {
+		return 0
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil && a.err == nil {
+		a.err = fmt.Errorf("'%s' isn't an integer: %s", key, v)
+	}
+
+	return i
+}
+
+func parseBMLine(line string) (string, *bmAttrs, error) {
+	attrs := &bmAttrs{values: map[string]string{}}
+
+	line = strings.TrimSpace(line)
+	tagEnd := strings.IndexAny(line, " \t")
+	if tagEnd < 0 {
+		return line, attrs, nil
+	}
+
+	tag := line[:tagEnd]
+	rest := line[tagEnd:]
+
+	for {
+		rest = strings.TrimLeft(rest, " \t")
+		if rest == "" {
+			break
+		}
+
+		eq := strings.IndexByte(rest, '=')
+		if eq < 0 {
+			return "", nil, fmt.Errorf("expected key=value, got: %s", rest)
+		}
+
+		key := rest[:eq]
+		rest = rest[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, "\"") {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return "", nil, fmt.Errorf("unterminated quote for '%s'", key)
+			}
+			value = rest[1 : end+1]
+			rest = rest[end+2:]
+		} else {
+			end := strings.IndexAny(rest, " \t")
+			if end < 0 {
+				end = len(rest)
+			}
+			value = rest[:end]
+			rest = rest[end:]
+		}
+
+		attrs.values[key] = value
+	}
+
+	return tag, attrs, nil
+}
+
+// ---------------------------------------------------------------------
+// Binary format (version 3)
+// ---------------------------------------------------------------------
+
+const (
+	bmBlockInfo     = 1
+	bmBlockCommon   = 2
+	bmBlockPages    = 3
+	bmBlockChars    = 4
+	bmBlockKernings = 5
+
+	bmCharSize    = 20
+	bmKerningSize = 10
+)
+
+func parseBinaryBMFont(data []byte) (*BMFont, error) {
+	if len(data) < 4 || data[3] != 3 {
+		return nil, fmt.Errorf("unsupported binary BMFont version")
+	}
+
+	bf := new(BMFont)
+	le := binary.LittleEndian
+	pageCount := 0
+
+	data = data[4:]
+	for len(data) > 0 {
+		if len(data) < 5 {
+			return nil, fmt.Errorf("truncated block header")
+		}
+
+		blockType := data[0]
+		size := int(le.Uint32(data[1:5]))
+		data = data[5:]
+		if size > len(data) {
+			return nil, fmt.Errorf("block %d is truncated", blockType)
+		}
+		block := data[:size]
+		data = data[size:]
+
+		switch blockType {
+		case bmBlockInfo:
+			if len(block) < 15 {
+				return nil, fmt.Errorf("info block is too short")
+			}
+			bf.Size = int(int16(le.Uint16(block[0:])))
+			bf.Face = nulString(block[14:])
+		case bmBlockCommon:
+			if len(block) < 10 {
+				return nil, fmt.Errorf("common block is too short")
+			}
+			bf.LineHeight = int(le.Uint16(block[0:]))
+			bf.Base = int(le.Uint16(block[2:]))
+			bf.ScaleW = int(le.Uint16(block[4:]))
+			bf.ScaleH = int(le.Uint16(block[6:]))
+			pageCount = int(le.Uint16(block[8:]))
+		case bmBlockPages:
+			for len(block) > 0 {
+				name := nulString(block)
+				bf.Pages = append(bf.Pages, name)
+				if len(name) >= len(block) {
+					break
+				}
+				block = block[len(name)+1:]
+			}
+		case bmBlockChars:
+			for i := 0; i+bmCharSize <= len(block); i += bmCharSize {
+				c := block[i:]
+				bf.Chars = append(bf.Chars, BMChar{
+					ID:       rune(le.Uint32(c[0:])),
+					X:        int(le.Uint16(c[4:])),
+					Y:        int(le.Uint16(c[6:])),
+					Width:    int(le.Uint16(c[8:])),
+					Height:   int(le.Uint16(c[10:])),
+					XOffset:  int(int16(le.Uint16(c[12:]))),
+					YOffset:  int(int16(le.Uint16(c[14:]))),
+					XAdvance: int(int16(le.Uint16(c[16:]))),
+					Page:     int(c[18]),
+					Channel:  int(c[19]),
+				})
+			}
+		case bmBlockKernings:
+			for i := 0; i+bmKerningSize <= len(block); i += bmKerningSize {
+				k := block[i:]
+				bf.Kernings = append(bf.Kernings, BMKerning{
+					First:  rune(le.Uint32(k[0:])),
+					Second: rune(le.Uint32(k[4:])),
+					Amount: int(int16(le.Uint16(k[8:]))),
+				})
+			}
+		}
+	}
+
+	if bf.LineHeight == 0 {
+		return nil, fmt.Errorf("missing common block")
+	}
+
+	if len(bf.Pages) != pageCount {
+		return nil, fmt.Errorf("expected %d page names, got: %d", pageCount, len(bf.Pages))
+	}
+
+	return bf, nil
+}
+
+// nulString returns the bytes up to a NUL terminator.
+func nulString(b []byte) string {
+	if end := bytes.IndexByte(b, 0); end >= 0 {
+		return string(b[:end])
+	}
+	return string(b)
+}
@@ -0,0 +1,261 @@
+package fonts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testFnt = `info face="Test Font" size=-16 bold=0 italic=0 charset="" unicode=1 padding=0,0,0,0 spacing=1,1
+common lineHeight=18 base=14 scaleW=64 scaleH=64 pages=1 packed=0
+page id=0 file="test_0.png"
+chars count=2
+char id=65   x=0     y=0     width=10    height=12    xoffset=1     yoffset=2     xadvance=11    page=0  chnl=15
+char id=86   x=12    y=0     width=10    height=12    xoffset=0     yoffset=2     xadvance=10    page=0  chnl=15
+kernings count=1
+kerning first=65  second=86  amount=-2
+`
+
+func checkBMFont(t *testing.T, bf *BMFont) {
+	if bf.Face != "Test Font" || bf.Size != -16 {
+		t.Errorf("Expected face 'Test Font' size -16, got: '%s' %d", bf.Face, bf.Size)
+	}
+
+	if bf.LineHeight != 18 || bf.Base != 14 || bf.ScaleW != 64 || bf.ScaleH != 64 {
+		t.Errorf("Expected common 18 14 64 64, got: %d %d %d %d", bf.LineHeight, bf.Base, bf.ScaleW, bf.ScaleH)
+	}
+
+	if len(bf.Pages) != 1 || bf.Pages[0] != "test_0.png" {
+		t.Errorf("Expected page test_0.png, got: %v", bf.Pages)
+	}
+
+	if len(bf.Chars) != 2 {
+		t.Fatalf("Expected 2 chars, got: %d", len(bf.Chars))
+	}
+
+	expected := BMChar{ID: 'V', X: 12, Y: 0, Width: 10, Height: 12, XOffset: 0, YOffset: 2, XAdvance: 10, Page: 0, Channel: 15}
+	if bf.Chars[1] != expected {
+		t.Errorf("Expected %v, got: %v", expected, bf.Chars[1])
+	}
+
+	if len(bf.Kernings) != 1 || bf.Kernings[0] != (BMKerning{'A', 'V', -2}) {
+		t.Errorf("Expected kerning A V -2, got: %v", bf.Kernings)
+	}
+}
+
+func Test_BMFont_Text(t *testing.T) {
+	bf, err := ParseBMFont([]byte(testFnt))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkBMFont(t, bf)
+}
+
+// binaryFnt encodes the same font as testFnt in the binary format.
+func binaryFnt() []byte {
+	var buf bytes.Buffer
+	le := binary.LittleEndian
+
+	buf.WriteString("BMF")
+	buf.WriteByte(3)
+
+	block := func(blockType byte, data []byte) {
+		buf.WriteByte(blockType)
+		binary.Write(&buf, le, uint32(len(data)))
+		buf.Write(data)
+	}
+
+	var info bytes.Buffer
+	binary.Write(&info, le, int16(-16))
+	info.Write(make([]byte, 12))
+	info.WriteString("Test Font\x00")
+	block(1, info.Bytes())
+
+	var common bytes.Buffer
+	binary.Write(&common, le, []uint16{18, 14, 64, 64, 1})
+	common.Write(make([]byte, 5))
+	block(2, common.Bytes())
+
+	block(3, []byte("test_0.png\x00"))
+
+	var chars bytes.Buffer
+	for _, c := range []BMChar{
+		{'A', 0, 0, 10, 12, 1, 2, 11, 0, 15},
+		{'V', 12, 0, 10, 12, 0, 2, 10, 0, 15},
+	} {
+		binary.Write(&chars, le, uint32(c.ID))
+		binary.Write(&chars, le, []uint16{uint16(c.X), uint16(c.Y), uint16(c.Width), uint16(c.Height)})
+		binary.Write(&chars, le, []int16{int16(c.XOffset), int16(c.YOffset), int16(c.XAdvance)})
+		chars.WriteByte(byte(c.Page))
+		chars.WriteByte(byte(c.Channel))
+	}
+	block(4, chars.Bytes())
+
+	var kernings bytes.Buffer
+	binary.Write(&kernings, le, []uint32{'A', 'V'})
+	binary.Write(&kernings, le, int16(-2))
+	block(5, kernings.Bytes())
+
+	return buf.Bytes()
+}
+
+func Test_BMFont_Binary(t *testing.T) {
+	bf, err := ParseBMFont(binaryFnt())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkBMFont(t, bf)
+}
+
+func Test_BMFont_Errors(t *testing.T) {
+	if _, err := ParseBMFont([]byte("info face=\"x size=1")); err == nil {
+		t.Errorf("Expected an error for an unterminated quote")
+	}
+
+	if _, err := ParseBMFont([]byte("common lineHeight=big")); err == nil {
+		t.Errorf("Expected an error for a non integer")
+	}
+
+	if _, err := ParseBMFont([]byte("BMF\x02")); err == nil {
+		t.Errorf("Expected an error for binary version 2")
+	}
+}
+
+func Test_Load_BMFont(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bmfont")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fnt := filepath.Join(dir, "test.fnt")
+	if err := ioutil.WriteFile(fnt, []byte(testFnt), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := os.Create(filepath.Join(dir, "test_0.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	png.Encode(page, image.NewNRGBA(image.Rect(0, 0, 64, 64)))
+	page.Close()
+
+	ga, err := LoadBMFont(fnt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ga.Size != 16 || ga.LineHeight != 18 || ga.Ascent != 14 || ga.Descent != 4 {
+		t.Errorf("Expected metrics 16 18 14 4, got: %f %f %f %f", ga.Size, ga.LineHeight, ga.Ascent, ga.Descent)
+	}
+
+	a := ga.Glyph('A')
+	if a == nil || a.Region != image.Rect(0, 0, 10, 12) || a.BearingX != 1 || a.BearingY != 12 || a.Advance != 11 {
+		t.Errorf("Expected 'A' at (0,0)-(10,12) bearing 1,12 advance 11, got: %v", a)
+	}
+
+	// Laid out with the same API as TrueType atlases.
+	tl := LayoutText(ga, "AV", LayoutOptions{})
+	if tl.Glyphs[1].X != 9 {
+		t.Errorf("Expected kerned 'V' at 9, got: %f", tl.Glyphs[1].X)
+	}
+
+	if quads := BuildQuads(ga, "AV", AlignLeft); len(quads) != 2 {
+		t.Errorf("Expected 2 quads, got: %d", len(quads))
+	}
+}
+
+func Test_BMFont_GrayscaleCoverage(t *testing.T) {
+	bf, err := ParseBMFont([]byte(testFnt))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Opaque, the coverage is the luminance.
+	page := image.NewGray(image.Rect(0, 0, 64, 64))
+	page.Pix[page.PixOffset(1, 1)] = 200
+
+	ga, err := bf.Atlas([]image.Image{page})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c := ga.Pages[0].NRGBAAt(1, 1); c != (color.NRGBA{255, 255, 255, 200}) {
+		t.Errorf("Expected white with alpha 200, got: %v", c)
+	}
+	if c := ga.Pages[0].NRGBAAt(2, 2); c.A != 0 {
+		t.Errorf("Expected a black texel to be transparent, got: %v", c)
+	}
+}
+
+func Test_BMFont_PackedChannels(t *testing.T) {
+	bf, err := ParseBMFont([]byte(testFnt))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both chars share a region, 'A' in red and 'V' in green.
+	bf.Chars[0].Channel = 4
+	bf.Chars[1].X = 0
+	bf.Chars[1].Channel = 2
+
+	page := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	page.SetNRGBA(1, 1, color.NRGBA{R: 255, G: 0, B: 0, A: 255})
+	page.SetNRGBA(2, 2, color.NRGBA{R: 0, G: 128, B: 0, A: 255})
+
+	ga, err := bf.Atlas([]image.Image{page})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, v := ga.Glyph('A'), ga.Glyph('V')
+	if a.Page == v.Page || len(ga.Pages) != 3 {
+		t.Fatalf("Expected a page per channel, got: %d %d of %d", a.Page, v.Page, len(ga.Pages))
+	}
+
+	red, green := ga.Pages[a.Page], ga.Pages[v.Page]
+	if red.NRGBAAt(1, 1).A != 255 || red.NRGBAAt(2, 2).A != 0 {
+		t.Errorf("Expected 'A' coverage from red, got: %v %v", red.NRGBAAt(1, 1), red.NRGBAAt(2, 2))
+	}
+	if green.NRGBAAt(2, 2).A != 128 || green.NRGBAAt(1, 1).A != 0 {
+		t.Errorf("Expected 'V' coverage from green, got: %v %v", green.NRGBAAt(2, 2), green.NRGBAAt(1, 1))
+	}
+}
+
+func Test_NewBMFont_Kernings(t *testing.T) {
+	ga := newTestAtlas(t)
+
+	// The TrueType atlas kerns on demand, the export must ask for every pair.
+	expected := map[[2]rune]int{}
+	for first := range ga.Glyphs {
+		for second := range ga.Glyphs {
+			if k := round(ga.Kern(first, second)); k != 0 {
+				expected[[2]rune{first, second}] = k
+			}
+		}
+	}
+
+	if len(expected) == 0 {
+		t.Fatal("Expected the font to have kerning pairs")
+	}
+
+	bf := NewBMFont(ga, []string{"neuropol_0.png"})
+
+	if len(bf.Kernings) != len(expected) {
+		t.Fatalf("Expected %d kernings, got: %d", len(expected), len(bf.Kernings))
+	}
+
+	for _, k := range bf.Kernings {
+		if expected[[2]rune{k.First, k.Second}] != k.Amount {
+			t.Errorf("Expected '%c' '%c' kerning %d, got: %d", k.First, k.Second, expected[[2]rune{k.First, k.Second}], k.Amount)
+		}
+	}
+}
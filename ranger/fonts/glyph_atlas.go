@@ -0,0 +1,176 @@
+package fonts
+
+import (
+	"image"
+)
+
+// Glyph locates a rasterised rune within a GlyphAtlas page.
+type Glyph struct {
+	Rune rune
+	Page int
+	// Region is the glyph's area of the page in texels.
+	Region image.Rectangle
+
+	// BearingX,BearingY is the offset from the pen position on the baseline
+	// to the upper left corner of Region, with +Y upwards.
+	BearingX, BearingY float32
+
+	// Advance is how far the pen moves after this glyph.
+	Advance float32
+}
+
+// GlyphAtlas holds rasterised glyphs packed into one or more page images.
+// All metrics are in pixels at the atlas' Size.
+type GlyphAtlas struct {
+	Name string
+	Size float32
+
+	Pages  []*image.NRGBA
+	Glyphs map[rune]*Glyph
+
+	// LineHeight is the recommended distance between baselines.
+	LineHeight float32
+	Ascent     float32
+	Descent    float32
+
+	// DefaultScale maps atlas pixels to world units for text that isn't
+	// given a size, for example, FontObj.Scale.
+	DefaultScale float32
+
+	// kerning is keyed by rune pair.
+	kerning map[[2]rune]float32
+	// kernSource looks up pairs missing from kerning on demand, for
+	// example, from a TrueType face. Results are kept in kernCache.
+	kernSource func(left, right rune) float32
+	kernCache  map[[2]rune]float32
+
+	// Fallback is used for runes that aren't in the atlas.
+	Fallback rune
+
+	// DistanceRange is non-zero for signed distance field atlases. It is
+	// the distance, in pixels, spanned by alpha 0 -> 1 where 0.5 is the
+	// glyph's edge.
+	DistanceRange float32
+}
+
+// NewGlyphAtlas creates an empty GlyphAtlas
+func NewGlyphAtlas(name string, size float32) *GlyphAtlas {
+	ga := new(GlyphAtlas)
+	ga.Name = name
+	ga.Size = size
+	ga.Glyphs = make(map[rune]*Glyph)
+	ga.DefaultScale = 1.0
+	ga.kerning = make(map[[2]rune]float32)
+	ga.kernCache = make(map[[2]rune]float32)
+	ga.Fallback = '?'
+	return ga
+}
+
+// Glyph returns the glyph for a rune, the Fallback glyph or nil.
+func (ga *GlyphAtlas) Glyph(r rune) *Glyph {
+	if g, ok := ga.Glyphs[r]; ok {
+		return g
+	}
+
+	return ga.Glyphs[ga.Fallback]
+}
+
+// SetKern sets the kerning adjustment between two runes.
+func (ga *GlyphAtlas) SetKern(left, right rune, adjust float32) {
+	// A zero must be kept to override the kernSource.
+	if adjust == 0.0 && ga.kernSource == nil {
+		delete(ga.kerning, [2]rune{left, right})
+		return
+	}
+	ga.kerning[[2]rune{left, right}] = adjust
+}
+
+// Kern returns the adjustment to the advance between two runes.
+func (ga *GlyphAtlas) Kern(left, right rune) float32 {
+	pair := [2]rune{left, right}
+	if k, ok := ga.kerning[pair]; ok || ga.kernSource == nil {
+		return k
+	}
+
+	k, ok := ga.kernCache[pair]
+	if !ok {
+		k = ga.kernSource(left, right)
+		ga.kernCache[pair] = k
+	}
+	return k
+}
+
+// GlyphAdvance returns how far the pen moves after a rune or false if the
+// rune can't be drawn.
+func (ga *GlyphAtlas) GlyphAdvance(r rune) (float32, bool) {
+	g := ga.Glyph(r)
+	if g == nil {
+		return 0.0, false
+	}
+	return g.Advance, true
+}
+
+// LineMetrics returns the ascent, descent and line height.
+func (ga *GlyphAtlas) LineMetrics() (ascent, descent, lineHeight float32) {
+	return ga.Ascent, ga.Descent, ga.LineHeight
+}
+
+// CharSet returns the runes from ' ' up to, but not including, count.
+// For example, FontObj.CharsFromSet = 128 yields printable ASCII.
+func CharSet(count int) []rune {
+	runes := []rune{}
+	for r := rune(32); r < rune(count); r++ {
+		if r == 127 {
+			continue
+		}
+		runes = append(runes, r)
+	}
+	return runes
+}
+
+// ---------------------------------------------------------------------
+// Page packing
+// ---------------------------------------------------------------------
+
+// shelfPacker places glyphs left to right in rows (aka shelves).
+type shelfPacker struct {
+	width, height int
+	padding       int
+
+	x, y        int
+	shelfHeight int
+}
+
+func newShelfPacker(width, height, padding int) *shelfPacker {
+	sp := new(shelfPacker)
+	sp.width = width
+	sp.height = height
+	sp.padding = padding
+	sp.x = padding
+	sp.y = padding
+	return sp
+}
+
+// place returns the upper left position for a width x height region or
+// false if the page is full.
+func (sp *shelfPacker) place(width, height int) (image.Point, bool) {
+	if sp.x+width+sp.padding > sp.width {
+		// Next shelf
+		sp.x = sp.padding
+		sp.y += sp.shelfHeight + sp.padding
+		sp.shelfHeight = 0
+	}
+
+	if sp.y+height+sp.padding > sp.height || width+sp.padding*2 > sp.width {
+		return image.Point{}, false
+	}
+
+	p := image.Pt(sp.x, sp.y)
+
+	sp.x += width + sp.padding
+	if height > sp.shelfHeight {
+		sp.shelfHeight = height
+	}
+
+	return p, true
+}
@@ -0,0 +1,415 @@
+package fonts
+
+import (
+	"unicode"
+
+	"github.com/wdevore/ranger/rmath"
+)
+
+// Alignment of each line relative to the text's origin.
+type Alignment int
+
+const (
+	// AlignLeft starts lines at the origin
+	AlignLeft Alignment = iota
+	// AlignCenter centers lines on the origin
+	AlignCenter
+	// AlignRight ends lines at the origin
+	AlignRight
+	// AlignJustify stretches wrapped lines to the maximum width
+	AlignJustify
+)
+
+// Metrics is a font that can be laid out. GlyphAtlas, VectorFont and
+// StrokeFont implement it in their own units (pixels or ems).
+type Metrics interface {
+	// GlyphAdvance returns how far the pen moves after a rune or false if
+	// the font can't draw it.
+	GlyphAdvance(r rune) (float32, bool)
+	// Kern returns the adjustment to the advance between two runes.
+	Kern(left, right rune) float32
+	// LineMetrics returns the distances above and below the baseline (both
+	// positive) and the baseline to baseline distance.
+	LineMetrics() (ascent, descent, lineHeight float32)
+}
+
+// LayoutOptions control how text is laid out.
+type LayoutOptions struct {
+	Align Alignment
+
+	// MaxWidth wraps lines at word boundaries, or within a word when it is
+	// longer than a line. 0 disables wrapping.
+	MaxWidth float32
+
+	// LineSpacing multiplies the font's line height. 0 means 1.
+	LineSpacing float32
+}
+
+// GlyphPlacement is a rune positioned by layout. X is the pen position on
+// the baseline at Y, with +Y upwards where the first baseline is at Y = 0.
+type GlyphPlacement struct {
+	Rune rune
+	X, Y float32
+}
+
+// LineLayout is one line of a TextLayout.
+type LineLayout struct {
+	// Glyphs[Start:End] are the line's glyphs
+	Start, End int
+	// X is where the line starts after alignment
+	X, Y  float32
+	Width float32
+}
+
+// Run is a piece of text, or an icon, laid out in one style.
+type Run struct {
+	Text string
+	// Scale multiplies the font's size. 0 means 1.
+	Scale float32
+	// Icon names an inline image drawn instead of Text. It sits on the
+	// baseline and is as tall as the font's ascent.
+	Icon string
+	// Tag is copied to the run's glyphs so the caller can find their style,
+	// for example, their colour.
+	Tag int
+}
+
+// IconMetrics sizes inline icons, for example, a SpriteSheet.
+type IconMetrics interface {
+	// IconAspect returns an icon's width / height or false if it's unknown.
+	IconAspect(name string) (float32, bool)
+}
+
+// RichGlyph is a rune or icon positioned by LayoutRuns.
+type RichGlyph struct {
+	GlyphPlacement
+	Scale float32
+	// Icon is the name of an icon, in which case Rune is 0 and Width x
+	// Height is its size above the baseline.
+	Icon          string
+	Width, Height float32
+	Tag           int
+}
+
+// RichLayout is positioned runs. See TextLayout.
+type RichLayout struct {
+	Glyphs []RichGlyph
+	Lines  []LineLayout
+	Bounds rmath.Rectangle
+}
+
+// TextLayout is positioned text.
+type TextLayout struct {
+	Glyphs []GlyphPlacement
+	Lines  []LineLayout
+
+	// Bounds encloses the lines' ascent to descent with +Y upwards, so Top
+	// is above Bottom.
+	Bounds rmath.Rectangle
+}
+
+// LayoutText positions each rune of UTF-8 text. Lines are separated by
+// '\n' and possibly wrapped. Lines are aligned relative to X = 0; justified
+// lines fill 0 -> MaxWidth except the last line of a paragraph.
+func LayoutText(m Metrics, text string, options LayoutOptions) *TextLayout {
+	rl := LayoutRuns(m, []Run{{Text: text}}, nil, options)
+
+	tl := new(TextLayout)
+	tl.Lines = rl.Lines
+	tl.Bounds = rl.Bounds
+
+	tl.Glyphs = make([]GlyphPlacement, len(rl.Glyphs))
+	for i, g := range rl.Glyphs {
+		tl.Glyphs[i] = g.GlyphPlacement
+	}
+
+	return tl
+}
+
+// LayoutRuns lays out runs of differently sized text and inline icons as
+// one piece of text. See LayoutText. Taller runs push their line's baseline
+// down and the next line's baseline further down. 'icons' may be nil, in
+// which case icons are square.
+func LayoutRuns(m Metrics, runs []Run, icons IconMetrics, options LayoutOptions) *RichLayout {
+	rl := new(RichLayout)
+
+	ascent, descent, lineHeight := m.LineMetrics()
+	spacing := options.LineSpacing
+	if spacing == 0.0 {
+		spacing = 1.0
+	}
+
+	line := 0
+	left := float32(0.0)
+	right := float32(0.0)
+
+	// How much taller than the font's metrics all previous lines were.
+	extra := float32(0.0)
+	prevDescent := descent
+	firstAscent := ascent
+	lastDescent := descent
+
+	for _, paragraph := range layoutParagraphs(m, runs, icons) {
+		items := paragraph
+
+		for {
+			end, next := breakLine(m, items, options.MaxWidth)
+
+			lineAscent, lineDescent := lineExtent(items[:end], ascent, descent)
+			if line == 0 {
+				firstAscent = lineAscent
+			} else {
+				extra += (prevDescent - descent) + (lineAscent - ascent)
+			}
+			prevDescent = lineDescent
+			lastDescent = lineDescent
+
+			last := next >= len(items)
+			y := -(float32(line)*lineHeight + extra) * spacing
+			rl.addLine(m, items[:end], y, options, last)
+
+			l := rl.Lines[len(rl.Lines)-1]
+			if len(rl.Lines) == 1 || l.X < left {
+				left = l.X
+			}
+			if len(rl.Lines) == 1 || l.X+l.Width > right {
+				right = l.X + l.Width
+			}
+
+			line++
+			items = items[next:]
+			if last {
+				break
+			}
+		}
+	}
+
+	bottom := rl.Lines[len(rl.Lines)-1].Y - lastDescent
+	rl.Bounds.SetByComp(left, firstAscent, right, bottom)
+
+	return rl
+}
+
+// Measure returns the bounds of laid out text.
+func Measure(m Metrics, text string, options LayoutOptions) rmath.Rectangle {
+	return LayoutText(m, text, options).Bounds
+}
+
+// MeasureWidth returns the advance width of a single unwrapped line
+// including kerning.
+func MeasureWidth(m Metrics, line string) float32 {
+	return lineWidth(m, layoutParagraphs(m, []Run{{Text: line}}, nil)[0])
+}
+
+// layoutItem is a rune or icon with its style resolved.
+type layoutItem struct {
+	r    rune
+	icon string
+	tag  int
+
+	scale   float32
+	advance float32
+	// ok is false for runes the font can't draw and unknown icons.
+	ok bool
+	// Icon dimensions
+	width, height float32
+}
+
+func (li *layoutItem) isSpace() bool {
+	return li.icon == "" && unicode.IsSpace(li.r)
+}
+
+// kern returns the kerning between two items of the same size.
+func kern(m Metrics, left, right *layoutItem) float32 {
+	if left.icon != "" || right.icon != "" || left.scale != right.scale {
+		return 0.0
+	}
+	return m.Kern(left.r, right.r) * left.scale
+}
+
+// layoutParagraphs flattens runs into items split at '\n'. There is always
+// at least one, possibly empty, paragraph.
+func layoutParagraphs(m Metrics, runs []Run, icons IconMetrics) [][]layoutItem {
+	ascent, _, _ := m.LineMetrics()
+
+	paragraphs := [][]layoutItem{{}}
+	last := 0
+
+	for _, run := range runs {
+		scale := run.Scale
+		if scale == 0.0 {
+			scale = 1.0
+		}
+
+		if run.Icon != "" {
+			aspect, ok := float32(1.0), true
+			if icons != nil {
+				aspect, ok = icons.IconAspect(run.Icon)
+			}
+
+			height := ascent * scale
+			item := layoutItem{icon: run.Icon, tag: run.Tag, scale: scale, ok: ok,
+				width: height * aspect, height: height}
+			item.advance = item.width
+
+			paragraphs[last] = append(paragraphs[last], item)
+			continue
+		}
+
+		for _, r := range run.Text {
+			if r == '\n' {
+				paragraphs = append(paragraphs, []layoutItem{})
+				last++
+				continue
+			}
+
+			adv, ok := m.GlyphAdvance(r)
+			if scale != 1.0 {
+				adv *= scale
+			}
+
+			paragraphs[last] = append(paragraphs[last],
+				layoutItem{r: r, tag: run.Tag, scale: scale, advance: adv, ok: ok})
+		}
+	}
+
+	return paragraphs
+}
+
+// lineExtent returns the ascent and descent of the line's tallest item.
+// Empty lines use the font's.
+func lineExtent(items []layoutItem, ascent, descent float32) (float32, float32) {
+	lineAscent, lineDescent := ascent, descent
+
+	for i := range items {
+		it := &items[i]
+		if !it.ok || it.scale <= 1.0 {
+			continue
+		}
+		if a := ascent * it.scale; a > lineAscent {
+			lineAscent = a
+		}
+		if d := descent * it.scale; d > lineDescent {
+			lineDescent = d
+		}
+	}
+
+	return lineAscent, lineDescent
+}
+
+// breakLine returns how many items fit on the first line, excluding
+// trailing spaces, and where the next line starts.
+func breakLine(m Metrics, items []layoutItem, maxWidth float32) (end, next int) {
+	width := float32(0.0)
+	lastSpace := -1
+
+	for i := range items {
+		it := &items[i]
+		if !it.ok {
+			continue
+		}
+		adv := it.advance
+		if i > 0 {
+			adv += kern(m, &items[i-1], it)
+		}
+
+		space := it.isSpace()
+
+		if maxWidth > 0.0 && !space && width+adv > maxWidth && i > 0 {
+			if lastSpace >= 0 {
+				end = lastSpace
+				for end > 0 && items[end-1].isSpace() {
+					end--
+				}
+				return end, lastSpace + 1
+			}
+			// A word longer than the line.
+			return i, i
+		}
+
+		width += adv
+		if space {
+			lastSpace = i
+		}
+	}
+
+	return len(items), len(items)
+}
+
+func (rl *RichLayout) addLine(m Metrics, items []layoutItem, y float32, options LayoutOptions, last bool) {
+	width := lineWidth(m, items)
+
+	ll := LineLayout{Start: len(rl.Glyphs), Y: y, Width: width}
+
+	// Justified lines spread the remaining width over their spaces.
+	stretch := float32(0.0)
+	if options.Align == AlignJustify && !last && options.MaxWidth > 0.0 {
+		spaces := 0
+		for i := range items {
+			if items[i].isSpace() {
+				spaces++
+			}
+		}
+		if spaces > 0 {
+			stretch = (options.MaxWidth - width) / float32(spaces)
+			ll.Width = options.MaxWidth
+		}
+	}
+
+	ll.X = alignOffset(width, options.Align)
+
+	x := ll.X
+	for i := range items {
+		it := &items[i]
+		if i > 0 {
+			x += kern(m, &items[i-1], it)
+		}
+
+		if !it.ok {
+			continue
+		}
+
+		rl.Glyphs = append(rl.Glyphs, RichGlyph{
+			GlyphPlacement: GlyphPlacement{Rune: it.r, X: x, Y: y},
+			Scale:          it.scale,
+			Icon:           it.icon,
+			Width:          it.width,
+			Height:         it.height,
+			Tag:            it.tag,
+		})
+
+		x += it.advance
+		if it.isSpace() {
+			x += stretch
+		}
+	}
+
+	ll.End = len(rl.Glyphs)
+	rl.Lines = append(rl.Lines, ll)
+}
+
+func lineWidth(m Metrics, items []layoutItem) float32 {
+	width := float32(0.0)
+
+	for i := range items {
+		if i > 0 {
+			width += kern(m, &items[i-1], &items[i])
+		}
+
+		if items[i].ok {
+			width += items[i].advance
+		}
+	}
+
+	return width
+}
+
+func alignOffset(width float32, align Alignment) float32 {
+	switch align {
+	case AlignCenter:
+		return -width / 2.0
+	case AlignRight:
+		return -width
+	}
+	return 0.0
+}
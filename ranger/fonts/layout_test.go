@@ -0,0 +1,215 @@
+package fonts
+
+import (
+	"testing"
+)
+
+// monoMetrics is a fixed width font where every rune is 1 wide, lines are
+// 2 apart and "AV" kerns by -0.5.
+type monoMetrics struct{}
+
+func (monoMetrics) GlyphAdvance(r rune) (float32, bool) {
+	return 1.0, r != '\t'
+}
+
+func (monoMetrics) Kern(left, right rune) float32 {
+	if left == 'A' && right == 'V' {
+		return -0.5
+	}
+	return 0.0
+}
+
+func (monoMetrics) LineMetrics() (ascent, descent, lineHeight float32) {
+	return 1.5, 0.5, 2.0
+}
+
+func lineText(tl *TextLayout, line int) string {
+	l := tl.Lines[line]
+	runes := []rune{}
+	for _, g := range tl.Glyphs[l.Start:l.End] {
+		runes = append(runes, g.Rune)
+	}
+	return string(runes)
+}
+
+func Test_Layout_Wrap_Words(t *testing.T) {
+	tl := LayoutText(monoMetrics{}, "the quick brown fox", LayoutOptions{MaxWidth: 10})
+
+	expected := []string{"the quick", "brown fox"}
+	if len(tl.Lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got: %d", len(expected), len(tl.Lines))
+	}
+
+	for i, e := range expected {
+		if got := lineText(tl, i); got != e {
+			t.Errorf("Expected line %d '%s', got: '%s'", i, e, got)
+		}
+	}
+
+	if tl.Lines[1].Y != -2.0 {
+		t.Errorf("Expected second baseline at -2, got: %f", tl.Lines[1].Y)
+	}
+}
+
+func Test_Layout_Wrap_Long_Word(t *testing.T) {
+	tl := LayoutText(monoMetrics{}, "abcdefgh", LayoutOptions{MaxWidth: 3})
+
+	expected := []string{"abc", "def", "gh"}
+	if len(tl.Lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got: %d", len(expected), len(tl.Lines))
+	}
+
+	for i, e := range expected {
+		if got := lineText(tl, i); got != e {
+			t.Errorf("Expected line %d '%s', got: '%s'", i, e, got)
+		}
+	}
+}
+
+func Test_Layout_Alignment(t *testing.T) {
+	center := LayoutText(monoMetrics{}, "abcd\nab", LayoutOptions{Align: AlignCenter})
+
+	if center.Lines[0].X != -2.0 || center.Lines[1].X != -1.0 {
+		t.Errorf("Expected centered lines at -2 and -1, got: %f %f", center.Lines[0].X, center.Lines[1].X)
+	}
+
+	right := LayoutText(monoMetrics{}, "abcd", LayoutOptions{Align: AlignRight})
+	if right.Glyphs[3].X != -1.0 {
+		t.Errorf("Expected last glyph at -1, got: %f", right.Glyphs[3].X)
+	}
+}
+
+func Test_Layout_Justify(t *testing.T) {
+	tl := LayoutText(monoMetrics{}, "a b c dd", LayoutOptions{Align: AlignJustify, MaxWidth: 7})
+
+	// "a b c" is 5 wide with 2 spaces, each stretched by 1.
+	if got := lineText(tl, 0); got != "a b c" {
+		t.Fatalf("Expected 'a b c', got: '%s'", got)
+	}
+
+	if x := tl.Glyphs[4].X; x != 6.0 {
+		t.Errorf("Expected 'c' at 6, got: %f", x)
+	}
+
+	// The last line isn't stretched.
+	if tl.Lines[1].Width != 2.0 {
+		t.Errorf("Expected last line width 2, got: %f", tl.Lines[1].Width)
+	}
+}
+
+func Test_Layout_Kerning_And_UTF8(t *testing.T) {
+	tl := LayoutText(monoMetrics{}, "AVé世", LayoutOptions{})
+
+	if len(tl.Glyphs) != 4 {
+		t.Fatalf("Expected 4 glyphs, got: %d", len(tl.Glyphs))
+	}
+
+	if tl.Glyphs[1].X != 0.5 {
+		t.Errorf("Expected kerned 'V' at 0.5, got: %f", tl.Glyphs[1].X)
+	}
+
+	if tl.Glyphs[3].Rune != '世' || tl.Glyphs[3].X != 2.5 {
+		t.Errorf("Expected '世' at 2.5, got: '%c' at %f", tl.Glyphs[3].Rune, tl.Glyphs[3].X)
+	}
+}
+
+func Test_Layout_Measure(t *testing.T) {
+	bounds := Measure(monoMetrics{}, "abcd\nab", LayoutOptions{LineSpacing: 1.5})
+
+	// 2 lines 3 apart: top at the ascent, bottom at -3 - descent.
+	if bounds.Left != 0.0 || bounds.Right != 4.0 || bounds.Top != 1.5 || bounds.Bottom != -3.5 {
+		t.Errorf("Expected (0, 1.5, 4, -3.5), got: %v", bounds)
+	}
+
+	if bounds.Width != 4.0 || bounds.Height != 5.0 {
+		t.Errorf("Expected 4 x 5, got: %f x %f", bounds.Width, bounds.Height)
+	}
+
+	if w := MeasureWidth(monoMetrics{}, "AV"); w != 1.5 {
+		t.Errorf("Expected kerned width 1.5, got: %f", w)
+	}
+}
+
+// squareIcons are twice as wide as high except "tall" which is half.
+type squareIcons struct{}
+
+func (squareIcons) IconAspect(name string) (float32, bool) {
+	switch name {
+	case "wide":
+		return 2.0, true
+	case "tall":
+		return 0.5, true
+	}
+	return 0.0, false
+}
+
+func Test_LayoutRuns_Matches_LayoutText(t *testing.T) {
+	options := LayoutOptions{MaxWidth: 10, Align: AlignJustify}
+	text := "the quick brown fox\njumps AV"
+
+	tl := LayoutText(monoMetrics{}, text, options)
+	rl := LayoutRuns(monoMetrics{}, []Run{{Text: "the quick "}, {Text: "brown fox\njumps AV"}}, nil, options)
+
+	if len(tl.Glyphs) != len(rl.Glyphs) || len(tl.Lines) != len(rl.Lines) {
+		t.Fatalf("Expected %d glyphs on %d lines, got: %d on %d", len(tl.Glyphs), len(tl.Lines), len(rl.Glyphs), len(rl.Lines))
+	}
+
+	for i, g := range tl.Glyphs {
+		if rl.Glyphs[i].GlyphPlacement != g {
+			t.Errorf("Expected glyph %d at %v, got: %v", i, g, rl.Glyphs[i].GlyphPlacement)
+		}
+	}
+
+	if rl.Bounds != tl.Bounds {
+		t.Errorf("Expected bounds %v, got: %v", tl.Bounds, rl.Bounds)
+	}
+}
+
+func Test_LayoutRuns_Scale(t *testing.T) {
+	runs := []Run{{Text: "ab"}, {Text: "C", Scale: 2.0, Tag: 1}, {Text: "d\ne"}}
+	rl := LayoutRuns(monoMetrics{}, runs, nil, LayoutOptions{})
+
+	c := rl.Glyphs[2]
+	if c.Rune != 'C' || c.X != 2.0 || c.Scale != 2.0 || c.Tag != 1 {
+		t.Errorf("Expected 'C' at 2 scaled 2 tag 1, got: '%c' %f %f %d", c.Rune, c.X, c.Scale, c.Tag)
+	}
+
+	d := rl.Glyphs[3]
+	if d.X != 4.0 || d.Scale != 1.0 {
+		t.Errorf("Expected 'd' at 4 scale 1, got: %f %f", d.X, d.Scale)
+	}
+
+	// The first line's descent is doubled (0.5 -> 1.0) which pushes the
+	// second baseline down by 0.5.
+	if rl.Lines[1].Y != -2.5 {
+		t.Errorf("Expected second baseline at -2.5, got: %f", rl.Lines[1].Y)
+	}
+
+	// The first line's ascent is doubled too.
+	if rl.Bounds.Top != 3.0 || rl.Bounds.Bottom != -3.0 {
+		t.Errorf("Expected bounds 3 -> -3, got: %f -> %f", rl.Bounds.Top, rl.Bounds.Bottom)
+	}
+}
+
+func Test_LayoutRuns_Icons(t *testing.T) {
+	runs := []Run{{Text: "a"}, {Icon: "wide"}, {Icon: "missing"}, {Icon: "tall", Scale: 2.0}, {Text: "b"}}
+	rl := LayoutRuns(monoMetrics{}, runs, squareIcons{}, LayoutOptions{})
+
+	if len(rl.Glyphs) != 4 {
+		t.Fatalf("Expected 4 glyphs, got: %d", len(rl.Glyphs))
+	}
+
+	wide := rl.Glyphs[1]
+	if wide.Icon != "wide" || wide.X != 1.0 || wide.Width != 3.0 || wide.Height != 1.5 {
+		t.Errorf("Expected wide icon at 1 sized 3x1.5, got: %v", wide)
+	}
+
+	tall := rl.Glyphs[2]
+	if tall.X != 4.0 || tall.Width != 1.5 || tall.Height != 3.0 {
+		t.Errorf("Expected tall icon at 4 sized 1.5x3, got: %v", tall)
+	}
+
+	if b := rl.Glyphs[3]; b.X != 5.5 {
+		t.Errorf("Expected 'b' at 5.5, got: %f", b.X)
+	}
+}
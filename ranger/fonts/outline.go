@@ -0,0 +1,169 @@
+package fonts
+
+import (
+	"math"
+
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// Point is a 2D outline coordinate.
+type Point struct {
+	X, Y float32
+}
+
+// Contour is a closed outline. The last point connects back to the first.
+type Contour []Point
+
+// flattenTolerance is the maximum distance, in ems, between a curve and the
+// line segments replacing it.
+const flattenTolerance = 0.002
+
+// glyphContours loads a glyph's outline as flattened contours in ems with +Y
+// upwards. 'ppem' should be the font's UnitsPerEm so no precision is lost.
+func glyphContours(f *sfnt.Font, buf *sfnt.Buffer, index sfnt.GlyphIndex, ppem fixed.Int26_6) ([]Contour, error) {
+	segments, err := f.LoadGlyph(buf, index, ppem, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	scale := 1.0 / float32(ppem)
+	toPoint := func(p fixed.Point26_6) Point {
+		// sfnt uses +Y downwards.
+		return Point{X: float32(p.X) * scale, Y: -float32(p.Y) * scale}
+	}
+
+	contours := []Contour{}
+	var contour Contour
+
+	closeContour := func() {
+		contour = cleanContour(contour)
+		if len(contour) >= 3 {
+			contours = append(contours, contour)
+		}
+		contour = nil
+	}
+
+	for _, seg := range segments {
+		switch seg.Op {
+		case sfnt.SegmentOpMoveTo:
+			closeContour()
+			contour = Contour{toPoint(seg.Args[0])}
+		case sfnt.SegmentOpLineTo:
+			contour = append(contour, toPoint(seg.Args[0]))
+		case sfnt.SegmentOpQuadTo:
+			if len(contour) == 0 {
+				continue
+			}
+			contour = flattenQuad(contour, contour[len(contour)-1],
+				toPoint(seg.Args[0]), toPoint(seg.Args[1]))
+		case sfnt.SegmentOpCubeTo:
+			if len(contour) == 0 {
+				continue
+			}
+			contour = flattenCube(contour, contour[len(contour)-1],
+				toPoint(seg.Args[0]), toPoint(seg.Args[1]), toPoint(seg.Args[2]))
+		}
+	}
+
+	closeContour()
+
+	return contours, nil
+}
+
+// flattenQuad appends line segments approximating a quadratic bezier.
+// p0 is already in the contour.
+func flattenQuad(c Contour, p0, p1, p2 Point) Contour {
+	// The deviation of a chord from the curve is bounded by |p0-2p1+p2|/(8n^2)
+	dx := p0.X - 2*p1.X + p2.X
+	dy := p0.Y - 2*p1.Y + p2.Y
+	n := segmentCount(float32(math.Sqrt(float64(dx*dx + dy*dy))))
+
+	for i := 1; i <= n; i++ {
+		t := float32(i) / float32(n)
+		mt := 1 - t
+		c = append(c, Point{
+			X: mt*mt*p0.X + 2*mt*t*p1.X + t*t*p2.X,
+			Y: mt*mt*p0.Y + 2*mt*t*p1.Y + t*t*p2.Y,
+		})
+	}
+
+	return c
+}
+
+// flattenCube appends line segments approximating a cubic bezier.
+// p0 is already in the contour.
+func flattenCube(c Contour, p0, p1, p2, p3 Point) Contour {
+	dx := float32(math.Max(math.Abs(float64(p0.X-2*p1.X+p2.X)), math.Abs(float64(p1.X-2*p2.X+p3.X))))
+	dy := float32(math.Max(math.Abs(float64(p0.Y-2*p1.Y+p2.Y)), math.Abs(float64(p1.Y-2*p2.Y+p3.Y))))
+	// A cubic's bound is 3/4 * max second difference / n^2, six times the
+	// quadratic's.
+	n := segmentCount(6.0 * float32(math.Sqrt(float64(dx*dx+dy*dy))))
+
+	for i := 1; i <= n; i++ {
+		t := float32(i) / float32(n)
+		mt := 1 - t
+		c = append(c, Point{
+			X: mt*mt*mt*p0.X + 3*mt*mt*t*p1.X + 3*mt*t*t*p2.X + t*t*t*p3.X,
+			Y: mt*mt*mt*p0.Y + 3*mt*mt*t*p1.Y + 3*mt*t*t*p2.Y + t*t*t*p3.Y,
+		})
+	}
+
+	return c
+}
+
+// segmentCount returns how many chords keep a curve with the given second
+// difference within flattenTolerance.
+func segmentCount(secondDiff float32) int {
+	n := int(math.Ceil(math.Sqrt(float64(secondDiff / (8.0 * flattenTolerance)))))
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// cleanContour removes repeated points including a closing point that
+// duplicates the first.
+func cleanContour(c Contour) Contour {
+	if len(c) == 0 {
+		return c
+	}
+
+	cleaned := Contour{c[0]}
+	for _, p := range c[1:] {
+		if p != cleaned[len(cleaned)-1] {
+			cleaned = append(cleaned, p)
+		}
+	}
+
+	for len(cleaned) > 1 && cleaned[len(cleaned)-1] == cleaned[0] {
+		cleaned = cleaned[:len(cleaned)-1]
+	}
+
+	return cleaned
+}
+
+// Area returns the contour's signed area, positive when counter-clockwise.
+func (c Contour) Area() float32 {
+	area := float32(0.0)
+	for i := range c {
+		a := c[i]
+		b := c[(i+1)%len(c)]
+		area += a.X*b.Y - b.X*a.Y
+	}
+	return area / 2.0
+}
+
+// Contains reports whether p is inside the contour (even-odd rule).
+func (c Contour) Contains(p Point) bool {
+	inside := false
+	for i, j := 0, len(c)-1; i < len(c); j, i = i, i+1 {
+		a := c[i]
+		b := c[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) &&
+			p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
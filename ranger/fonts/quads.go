@@ -0,0 +1,81 @@
+package fonts
+
+// GlyphQuad is a positioned glyph ready for rendering. Positions are in
+// atlas pixels with +Y upwards where the first baseline is at Y = 0.
+type GlyphQuad struct {
+	Page int
+	// Lower left and upper right corners
+	X0, Y0, X1, Y1 float32
+	// Texture coordinates matching the corners (V = 0 is the page's top row)
+	U0, V0, U1, V1 float32
+	// Tag of the glyph's Run
+	Tag int
+}
+
+// BuildQuads positions each glyph of the text. Lines are separated by '\n'.
+func BuildQuads(ga *GlyphAtlas, text string, align Alignment) []GlyphQuad {
+	return ga.Quads(LayoutText(ga, text, LayoutOptions{Align: align}).Glyphs)
+}
+
+// Quads converts laid out glyphs to quads. Blank glyphs are skipped.
+func (ga *GlyphAtlas) Quads(placements []GlyphPlacement) []GlyphQuad {
+	quads := []GlyphQuad{}
+
+	for _, p := range placements {
+		g := ga.Glyph(p.Rune)
+		if g != nil && !g.Region.Empty() {
+			quads = append(quads, ga.quad(g, p.X, p.Y, 1.0))
+		}
+	}
+
+	return quads
+}
+
+// RichQuads converts laid out runs to quads. Blank glyphs and icons are
+// skipped.
+func (ga *GlyphAtlas) RichQuads(glyphs []RichGlyph) []GlyphQuad {
+	quads := []GlyphQuad{}
+
+	for _, rg := range glyphs {
+		if rg.Icon != "" {
+			continue
+		}
+
+		g := ga.Glyph(rg.Rune)
+		if g != nil && !g.Region.Empty() {
+			q := ga.quad(g, rg.X, rg.Y, rg.Scale)
+			q.Tag = rg.Tag
+			quads = append(quads, q)
+		}
+	}
+
+	return quads
+}
+
+// MeasureLine returns the advance width of a single line including kerning.
+func MeasureLine(ga *GlyphAtlas, line string) float32 {
+	return MeasureWidth(ga, line)
+}
+
+// quad positions a glyph, scaled about the pen, with the pen at x on
+// baseline y.
+func (ga *GlyphAtlas) quad(g *Glyph, x, y, scale float32) GlyphQuad {
+	page := ga.Pages[g.Page].Bounds()
+	pw := float32(page.Dx())
+	ph := float32(page.Dy())
+
+	left := x + g.BearingX*scale
+	top := y + g.BearingY*scale
+
+	return GlyphQuad{
+		Page: g.Page,
+		X0:   left,
+		Y0:   top - float32(g.Region.Dy())*scale,
+		X1:   left + float32(g.Region.Dx())*scale,
+		Y1:   top,
+		U0:   float32(g.Region.Min.X) / pw,
+		V0:   float32(g.Region.Max.Y) / ph,
+		U1:   float32(g.Region.Max.X) / pw,
+		V1:   float32(g.Region.Min.Y) / ph,
+	}
+}
@@ -0,0 +1,220 @@
+package fonts
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/wdevore/ranger/config"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Glyphs are rasterised this many times larger than the atlas size before
+// their distance field is sampled down.
+const sdfUpscale = 4
+
+// DefaultDistanceRange is the distance, in atlas pixels, a generated field
+// spans. Outlines and glows can't be wider than half of it.
+const DefaultDistanceRange = 8
+
+// NewSDFAtlasFromSettings generates a signed distance field atlas of the
+// font described by the font settings.
+func NewSDFAtlasFromSettings(settings *config.FontObj) (*GlyphAtlas, error) {
+	ttf, err := LoadFont(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSDFAtlas(settings.Name, ttf, float32(settings.Size), DefaultDistanceRange, CharSet(settings.CharsFromSet))
+}
+
+// NewSDFAtlas generates a signed distance field of each rune of a TTF at
+// size pixels. Pages are white with the distance in the alpha channel:
+// 0.5 is the edge, larger is inside. 'distanceRange' is in pixels.
+func NewSDFAtlas(name string, ttf []byte, size float32, distanceRange int, runes []rune) (*GlyphAtlas, error) {
+	f, err := opentype.Parse(ttf)
+	if err != nil {
+		return nil, err
+	}
+
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    float64(size * sdfUpscale),
+		DPI:     72.0,
+		Hinting: font.HintingNone,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The face isn't closed, the atlas kerns with it.
+
+	const down = 1.0 / sdfUpscale
+
+	ga := NewGlyphAtlas(name, size)
+	ga.DistanceRange = float32(distanceRange)
+
+	metrics := face.Metrics()
+	ga.Ascent = fixedToFloat(metrics.Ascent) * down
+	ga.Descent = fixedToFloat(metrics.Descent) * down
+	ga.LineHeight = fixedToFloat(metrics.Height) * down
+
+	// Glyphs are padded so the field can fade out around them.
+	padding := (distanceRange + 1) / 2
+
+	rasters := []*rasterGlyph{}
+	area := 0
+
+	for _, r := range runes {
+		dr, mask, maskp, advance, ok := face.Glyph(fixed.Point26_6{}, r)
+		if !ok {
+			continue
+		}
+
+		g := &Glyph{Rune: r, Advance: fixedToFloat(advance) * down}
+		ga.Glyphs[r] = g
+
+		if dr.Empty() {
+			continue
+		}
+
+		field, origin := distanceField(mask, maskp, dr, padding, float32(distanceRange))
+
+		// The field's upper left corner relative to the pen.
+		g.BearingX = float32(origin.X) * down
+		g.BearingY = float32(-origin.Y) * down
+
+		fieldSize := field.Bounds().Size()
+		rasters = append(rasters, &rasterGlyph{glyph: g, mask: field, bounds: field.Bounds()})
+		area += (fieldSize.X + glyphPadding) * (fieldSize.Y + glyphPadding)
+	}
+
+	ga.packGlyphs(rasters, pageSizeFor(area))
+	ga.kernFace(face, down)
+
+	return ga, nil
+}
+
+// distanceField samples a high resolution coverage mask, positioned at dr
+// relative to the pen, into a padded low resolution distance field. It
+// returns the field and the high resolution position of its upper left.
+func distanceField(mask image.Image, maskp image.Point, dr image.Rectangle, padding int, distanceRange float32) (*image.Alpha, image.Point) {
+	hiPadding := padding * sdfUpscale
+
+	// Field texels cover whole upscaled blocks.
+	width := (dr.Dx() + 2*hiPadding + sdfUpscale - 1) / sdfUpscale
+	height := (dr.Dy() + 2*hiPadding + sdfUpscale - 1) / sdfUpscale
+	hiWidth := width * sdfUpscale
+	hiHeight := height * sdfUpscale
+
+	// Threshold the coverage into inside/outside.
+	coverage := image.NewAlpha(image.Rect(0, 0, dr.Dx(), dr.Dy()))
+	draw.Draw(coverage, coverage.Bounds(), mask, maskp, draw.Src)
+
+	toInside := make([]float64, hiWidth*hiHeight)
+	toOutside := make([]float64, hiWidth*hiHeight)
+	for y := 0; y < hiHeight; y++ {
+		for x := 0; x < hiWidth; x++ {
+			i := y*hiWidth + x
+			if coverage.AlphaAt(x-hiPadding, y-hiPadding).A >= 128 {
+				toInside[i] = 0.0
+				toOutside[i] = edtInfinity
+			} else {
+				toInside[i] = edtInfinity
+				toOutside[i] = 0.0
+			}
+		}
+	}
+
+	edt(toInside, hiWidth, hiHeight)
+	edt(toOutside, hiWidth, hiHeight)
+
+	field := image.NewAlpha(image.Rect(0, 0, width, height))
+	scale := float64(distanceRange * sdfUpscale)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			// Sample the center of the texel's block.
+			i := (y*sdfUpscale+sdfUpscale/2)*hiWidth + x*sdfUpscale + sdfUpscale/2
+
+			// Positive inside, in upscaled pixels.
+			d := math.Sqrt(toOutside[i]) - math.Sqrt(toInside[i])
+			// Distances are between pixel centers, the edge is half way.
+			if d > 0 {
+				d -= 0.5
+			} else {
+				d += 0.5
+			}
+
+			v := 0.5 + d/scale
+			v = math.Max(0.0, math.Min(1.0, v))
+
+			field.Pix[y*field.Stride+x] = uint8(v*255.0 + 0.5)
+		}
+	}
+
+	return field, dr.Min.Sub(image.Pt(hiPadding, hiPadding))
+}
+
+const edtInfinity = 1e20
+
+// edt replaces each value with the squared euclidean distance to the
+// nearest zero using Felzenszwalb and Huttenlocher's separable transform.
+func edt(grid []float64, width, height int) {
+	n := width
+	if height > n {
+		n = height
+	}
+
+	f := make([]float64, n)
+	d := make([]float64, n)
+	v := make([]int, n)
+	z := make([]float64, n+1)
+
+	// Columns
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			f[y] = grid[y*width+x]
+		}
+		edt1D(f, d, v, z, height)
+		for y := 0; y < height; y++ {
+			grid[y*width+x] = d[y]
+		}
+	}
+
+	// Rows
+	for y := 0; y < height; y++ {
+		copy(f, grid[y*width:(y+1)*width])
+		edt1D(f, d, v, z, width)
+		copy(grid[y*width:(y+1)*width], d[:width])
+	}
+}
+
+// edt1D computes the lower envelope of parabolas rooted at each f[q].
+func edt1D(f, d []float64, v []int, z []float64, n int) {
+	k := 0
+	v[0] = 0
+	z[0] = -edtInfinity
+	z[1] = edtInfinity
+
+	for q := 1; q < n; q++ {
+		s := ((f[q] + float64(q*q)) - (f[v[k]] + float64(v[k]*v[k]))) / float64(2*q-2*v[k])
+		for s <= z[k] {
+			k--
+			s = ((f[q] + float64(q*q)) - (f[v[k]] + float64(v[k]*v[k]))) / float64(2*q-2*v[k])
+		}
+		k++
+		v[k] = q
+		z[k] = s
+		z[k+1] = edtInfinity
+	}
+
+	k = 0
+	for q := 0; q < n; q++ {
+		for z[k+1] < float64(q) {
+			k++
+		}
+		dq := float64(q - v[k])
+		d[q] = dq*dq + f[v[k]]
+	}
+}
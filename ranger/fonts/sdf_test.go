@@ -0,0 +1,129 @@
+package fonts
+
+import (
+	"bytes"
+	"image"
+	"math"
+	"testing"
+)
+
+func Test_EDT1D(t *testing.T) {
+	f := []float64{edtInfinity, edtInfinity, 0.0, edtInfinity, edtInfinity, edtInfinity, 0.0}
+	n := len(f)
+	d := make([]float64, n)
+	v := make([]int, n)
+	z := make([]float64, n+1)
+
+	edt1D(f, d, v, z, n)
+
+	expected := []float64{4, 1, 0, 1, 4, 1, 0}
+	for i, e := range expected {
+		if d[i] != e {
+			t.Errorf("Expected d[%d] = %f, got: %f", i, e, d[i])
+		}
+	}
+}
+
+func Test_SDFAtlas_Field(t *testing.T) {
+	ttf, err := Neuropol()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ga, err := NewSDFAtlas("Neuropol", ttf, 32.0, DefaultDistanceRange, []rune("I "))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ga.DistanceRange != DefaultDistanceRange {
+		t.Errorf("Expected DistanceRange %d, got: %f", DefaultDistanceRange, ga.DistanceRange)
+	}
+
+	g := ga.Glyph('I')
+	if g == nil || g.Region.Empty() {
+		t.Fatalf("Expected a region for 'I'")
+	}
+
+	page := ga.Pages[g.Page]
+	alpha := func(x, y int) uint8 {
+		return page.NRGBAAt(x, y).A
+	}
+
+	r := g.Region
+	center := alpha((r.Min.X+r.Max.X)/2, (r.Min.Y+r.Max.Y)/2)
+	if center <= 128 {
+		t.Errorf("Expected the center of 'I' inside (> 128), got: %d", center)
+	}
+
+	corners := []uint8{
+		alpha(r.Min.X, r.Min.Y),
+		alpha(r.Max.X-1, r.Min.Y),
+		alpha(r.Min.X, r.Max.Y-1),
+		alpha(r.Max.X-1, r.Max.Y-1),
+	}
+	for i, c := range corners {
+		if c >= 128 {
+			t.Errorf("Expected corner %d outside (< 128), got: %d", i, c)
+		}
+	}
+
+	// The padded field sits the same distance from the pen as a plain raster.
+	plain, err := NewTrueTypeAtlas("Neuropol", ttf, 32.0, []rune("I"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d := math.Abs(float64(plain.Glyph('I').Advance - g.Advance)); d > 0.5 {
+		t.Errorf("Expected SDF advance near %f, got: %f", plain.Glyph('I').Advance, g.Advance)
+	}
+}
+
+func Test_SDFAtlas_BMFontRoundTrip(t *testing.T) {
+	ttf, err := Neuropol()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ga, err := NewSDFAtlas("Neuropol", ttf, 24.0, 6, CharSet(127))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err = NewBMFont(ga, []string{"neuropol_0.png"}).WriteText(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bf, err := ParseBMFont(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bf.DistanceRange != 6 {
+		t.Errorf("Expected distanceRange 6, got: %d", bf.DistanceRange)
+	}
+
+	if bf.Face != "Neuropol" || len(bf.Pages) != 1 {
+		t.Errorf("Expected face Neuropol with 1 page, got: '%s' %d", bf.Face, len(bf.Pages))
+	}
+
+	if len(bf.Chars) != len(ga.Glyphs) {
+		t.Errorf("Expected %d chars, got: %d", len(ga.Glyphs), len(bf.Chars))
+	}
+
+	loaded, err := bf.Atlas([]image.Image{ga.Pages[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.DistanceRange != 6 {
+		t.Errorf("Expected atlas DistanceRange 6, got: %f", loaded.DistanceRange)
+	}
+
+	// Whole pixel metrics survive unchanged.
+	g, lg := ga.Glyph('A'), loaded.Glyph('A')
+	if g.Region != lg.Region || math.Abs(float64(g.BearingY-lg.BearingY)) > 1.0 {
+		t.Errorf("Expected 'A' region %v bearing %f, got: %v %f", g.Region, g.BearingY, lg.Region, lg.BearingY)
+	}
+}
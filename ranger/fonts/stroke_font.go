@@ -0,0 +1,223 @@
+package fonts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// StrokeGlyph is a glyph drawn with line segments.
+type StrokeGlyph struct {
+	Rune rune
+
+	// Segments are x0,y0,x1,y1 line end points in ems with the pen at the
+	// origin on the baseline and +Y upwards.
+	Segments []float32
+
+	Advance float32
+}
+
+// StrokeFont is a Hershey style line font. It has no lowercase, lowercase
+// runes are drawn with their uppercase glyph.
+type StrokeFont struct {
+	Name   string
+	Glyphs map[rune]*StrokeGlyph
+
+	LineHeight float32
+	Ascent     float32
+	Descent    float32
+
+	// Fallback is used for runes that aren't in the font.
+	Fallback rune
+}
+
+// The built-in glyphs are defined on a grid where capitals are 4 x 6 and
+// an em is 8 units.
+const strokeEm = 8.0
+
+// strokeGlyphs defines each glyph as polylines separated by ';' of "x,y"
+// grid points separated by spaces.
+var strokeGlyphs = map[rune]string{
+	' ':  "",
+	'!':  "2,6 2,2; 2,1 2,0",
+	'"':  "1,6 1,5; 3,6 3,5",
+	'#':  "1,0 1,6; 3,0 3,6; 0,2 4,2; 0,4 4,4",
+	'$':  "4,5 0,5 0,3 4,3 4,1 0,1; 2,6 2,0",
+	'%':  "0,0 4,6; 0,6 1,6 1,5 0,5 0,6; 3,1 4,1 4,0 3,0 3,1",
+	'&':  "4,0 0,4 0,6 2,6 2,4 0,2 0,0 2,0 4,2",
+	'\'': "2,6 2,5",
+	'(':  "3,6 1,4 1,2 3,0",
+	')':  "1,6 3,4 3,2 1,0",
+	'*':  "2,1 2,5; 0,3 4,3; 1,2 3,4; 1,4 3,2",
+	'+':  "2,1 2,5; 0,3 4,3",
+	',':  "2,1 2,0 1,-1",
+	'-':  "0,3 4,3",
+	'.':  "2,1 2,0",
+	'/':  "0,0 4,6",
+	'0':  "0,0 0,6 4,6 4,0 0,0; 0,0 4,6",
+	'1':  "1,5 2,6 2,0; 1,0 3,0",
+	'2':  "0,6 4,6 4,3 0,3 0,0 4,0",
+	'3':  "0,6 4,6 4,0 0,0; 0,3 4,3",
+	'4':  "0,6 0,3 4,3; 4,6 4,0",
+	'5':  "4,6 0,6 0,3 4,3 4,0 0,0",
+	'6':  "4,6 0,6 0,0 4,0 4,3 0,3",
+	'7':  "0,6 4,6 2,0",
+	'8':  "0,0 0,6 4,6 4,0 0,0; 0,3 4,3",
+	'9':  "4,0 4,6 0,6 0,3 4,3",
+	':':  "2,4 2,5; 2,1 2,2",
+	';':  "2,4 2,5; 2,2 2,1 1,0",
+	'<':  "4,6 0,3 4,0",
+	'=':  "0,2 4,2; 0,4 4,4",
+	'>':  "0,6 4,3 0,0",
+	'?':  "0,5 1,6 3,6 4,5 4,4 2,3 2,2; 2,1 2,0",
+	'@':  "3,2 1,2 1,4 3,4 3,1 4,1 4,6 0,6 0,0 4,0",
+	'A':  "0,0 0,4 2,6 4,4 4,0; 0,3 4,3",
+	'B':  "0,0 0,6 3,6 4,5 4,4 3,3 0,3; 3,3 4,2 4,1 3,0 0,0",
+	'C':  "4,0 0,0 0,6 4,6",
+	'D':  "0,0 0,6 2,6 4,4 4,2 2,0 0,0",
+	'E':  "4,0 0,0 0,6 4,6; 0,3 3,3",
+	'F':  "0,0 0,6 4,6; 0,3 3,3",
+	'G':  "2,2 4,2 4,0 0,0 0,6 4,6 4,4",
+	'H':  "0,0 0,6; 4,0 4,6; 0,3 4,3",
+	'I':  "0,0 4,0; 2,0 2,6; 0,6 4,6",
+	'J':  "0,2 1,0 3,0 4,2 4,6",
+	'K':  "0,0 0,6; 4,6 0,3 4,0",
+	'L':  "0,6 0,0 4,0",
+	'M':  "0,0 0,6 2,4 4,6 4,0",
+	'N':  "0,0 0,6 4,0 4,6",
+	'O':  "0,0 0,6 4,6 4,0 0,0",
+	'P':  "0,0 0,6 4,6 4,3 0,3",
+	'Q':  "0,0 0,6 4,6 4,2 2,0 0,0; 2,2 4,0",
+	'R':  "0,0 0,6 4,6 4,3 0,3; 1,3 4,0",
+	'S':  "0,0 4,0 4,3 0,3 0,6 4,6",
+	'T':  "0,6 4,6; 2,6 2,0",
+	'U':  "0,6 0,0 4,0 4,6",
+	'V':  "0,6 2,0 4,6",
+	'W':  "0,6 0,0 2,2 4,0 4,6",
+	'X':  "0,0 4,6; 0,6 4,0",
+	'Y':  "0,6 2,4 4,6; 2,4 2,0",
+	'Z':  "0,6 4,6 0,0 4,0",
+	'[':  "3,6 1,6 1,0 3,0",
+	'\\': "0,6 4,0",
+	']':  "1,6 3,6 3,0 1,0",
+	'^':  "0,4 2,6 4,4",
+	'_':  "0,0 4,0",
+	'`':  "1,6 2,5",
+	'{':  "3,6 2,6 1,5 1,4 0,3 1,2 1,1 2,0 3,0",
+	'|':  "2,0 2,6",
+	'}':  "1,6 2,6 3,5 3,4 4,3 3,2 3,1 2,0 1,0",
+	'~':  "0,3 1,4 3,2 4,3",
+}
+
+// NewStrokeFont creates the built-in stroke font.
+func NewStrokeFont() *StrokeFont {
+	sf := new(StrokeFont)
+	sf.Name = "Stroke"
+	sf.Glyphs = make(map[rune]*StrokeGlyph)
+	sf.Fallback = '?'
+
+	sf.Ascent = 6.0 / strokeEm
+	sf.Descent = 1.0 / strokeEm
+	sf.LineHeight = 10.0 / strokeEm
+
+	for r, def := range strokeGlyphs {
+		segments, err := ParseStrokes(def, 1.0/strokeEm)
+		if err != nil {
+			// The table is fixed so this is a programming error.
+			panic(fmt.Sprintf("stroke glyph '%c': %s", r, err))
+		}
+
+		sf.Glyphs[r] = &StrokeGlyph{
+			Rune:     r,
+			Segments: segments,
+			Advance:  6.0 / strokeEm,
+		}
+	}
+
+	return sf
+}
+
+// ParseStrokes converts polylines separated by ';' of space separated "x,y"
+// points into line segments, scaling each coordinate.
+func ParseStrokes(def string, scale float32) ([]float32, error) {
+	segments := []float32{}
+
+	for _, polyline := range strings.Split(def, ";") {
+		points := strings.Fields(polyline)
+		if len(points) == 1 {
+			return nil, fmt.Errorf("polyline '%s' needs at least 2 points", polyline)
+		}
+
+		var px, py float32
+		for i, point := range points {
+			xy := strings.Split(point, ",")
+			if len(xy) != 2 {
+				return nil, fmt.Errorf("point '%s' isn't x,y", point)
+			}
+
+			x, err := strconv.ParseFloat(xy[0], 32)
+			if err != nil {
+				return nil, err
+			}
+			y, err := strconv.ParseFloat(xy[1], 32)
+			if err != nil {
+				return nil, err
+			}
+
+			sx := float32(x) * scale
+			sy := float32(y) * scale
+
+			if i > 0 {
+				segments = append(segments, px, py, sx, sy)
+			}
+			px, py = sx, sy
+		}
+	}
+
+	return segments, nil
+}
+
+// Glyph returns the glyph for a rune, its uppercase glyph, the Fallback
+// glyph or nil.
+func (sf *StrokeFont) Glyph(r rune) *StrokeGlyph {
+	if g, ok := sf.Glyphs[r]; ok {
+		return g
+	}
+
+	if g, ok := sf.Glyphs[unicode.ToUpper(r)]; ok {
+		return g
+	}
+
+	return sf.Glyphs[sf.Fallback]
+}
+
+// Kern returns 0 as stroke glyphs are monospaced.
+func (sf *StrokeFont) Kern(left, right rune) float32 {
+	return 0.0
+}
+
+// Layout positions each glyph of the text in ems.
+func (sf *StrokeFont) Layout(text string, options LayoutOptions) *TextLayout {
+	return LayoutText(sf, text, options)
+}
+
+// MeasureLine returns the advance width of a single line in ems.
+func (sf *StrokeFont) MeasureLine(line string) float32 {
+	return MeasureWidth(sf, line)
+}
+
+// GlyphAdvance returns how far the pen moves after a rune or false if the
+// rune can't be drawn.
+func (sf *StrokeFont) GlyphAdvance(r rune) (float32, bool) {
+	g := sf.Glyph(r)
+	if g == nil {
+		return 0.0, false
+	}
+	return g.Advance, true
+}
+
+// LineMetrics returns the ascent, descent and line height.
+func (sf *StrokeFont) LineMetrics() (ascent, descent, lineHeight float32) {
+	return sf.Ascent, sf.Descent, sf.LineHeight
+}
@@ -0,0 +1,59 @@
+package fonts
+
+import (
+	"testing"
+)
+
+func Test_Stroke_Font_Coverage(t *testing.T) {
+	sf := NewStrokeFont()
+
+	for r := rune(32); r < 127; r++ {
+		g := sf.Glyph(r)
+		if g == nil {
+			t.Errorf("Expected a glyph for '%c'", r)
+			continue
+		}
+
+		if g.Rune != r && g.Rune != r-'a'+'A' {
+			t.Errorf("Expected '%c' to be drawn by itself or its uppercase, got: '%c'", r, g.Rune)
+		}
+
+		if len(g.Segments)%4 != 0 {
+			t.Errorf("Expected whole segments for '%c', got: %d values", r, len(g.Segments))
+		}
+
+		for i := 0; i < len(g.Segments); i += 2 {
+			x, y := g.Segments[i], g.Segments[i+1]
+			if x < 0.0 || x > g.Advance || y < -sf.Descent || y > sf.Ascent {
+				t.Errorf("Expected '%c' within its cell, got: %f,%f", r, x, y)
+			}
+		}
+	}
+}
+
+func Test_Parse_Strokes(t *testing.T) {
+	segments, err := ParseStrokes("0,0 4,0 4,4; 1,1 2,2", 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []float32{0, 0, 2, 0, 2, 0, 2, 2, 0.5, 0.5, 1, 1}
+	if len(segments) != len(expected) {
+		t.Fatalf("Expected %d values, got: %d", len(expected), len(segments))
+	}
+
+	for i := range expected {
+		if segments[i] != expected[i] {
+			t.Errorf("Expected %v, got: %v", expected, segments)
+			break
+		}
+	}
+
+	if _, err := ParseStrokes("0,0", 1.0); err == nil {
+		t.Errorf("Expected an error for a single point polyline")
+	}
+
+	if _, err := ParseStrokes("0,0 1", 1.0); err == nil {
+		t.Errorf("Expected an error for a malformed point")
+	}
+}
@@ -0,0 +1,233 @@
+package fonts
+
+import (
+	"math"
+	"sort"
+)
+
+// Triangulate fills contours using the even-odd rule and returns the
+// vertices and triangle indices. Contours nested inside an odd number of
+// others are holes.
+func Triangulate(contours []Contour) (vertices []Point, indices []uint32) {
+	// Each contour becomes a ring of indices into vertices.
+	rings := make([][]uint32, len(contours))
+	for i, c := range contours {
+		for _, p := range c {
+			rings[i] = append(rings[i], uint32(len(vertices)))
+			vertices = append(vertices, p)
+		}
+	}
+
+	depth := make([]int, len(contours))
+	for i, c := range contours {
+		for j, other := range contours {
+			if i != j && other.Contains(c[0]) {
+				depth[i]++
+			}
+		}
+	}
+
+	for i, c := range contours {
+		if depth[i]%2 != 0 {
+			continue
+		}
+
+		// Outer rings wind counter-clockwise and holes clockwise.
+		outer := rings[i]
+		if c.Area() < 0 {
+			outer = reverseRing(outer)
+		}
+
+		holes := [][]uint32{}
+		for j, h := range contours {
+			if depth[j] == depth[i]+1 && c.Contains(h[0]) {
+				hole := rings[j]
+				if h.Area() > 0 {
+					hole = reverseRing(hole)
+				}
+				holes = append(holes, hole)
+			}
+		}
+
+		// Bridge the right most holes first so later bridges can't cross them.
+		sort.Slice(holes, func(a, b int) bool {
+			return maxX(vertices, holes[a]) > maxX(vertices, holes[b])
+		})
+
+		for _, hole := range holes {
+			outer = bridgeHole(vertices, outer, hole)
+		}
+
+		indices = append(indices, earClip(vertices, outer)...)
+	}
+
+	return vertices, indices
+}
+
+func reverseRing(ring []uint32) []uint32 {
+	reversed := make([]uint32, len(ring))
+	for i, v := range ring {
+		reversed[len(ring)-1-i] = v
+	}
+	return reversed
+}
+
+func maxX(vertices []Point, ring []uint32) float32 {
+	m := float32(-math.MaxFloat32)
+	for _, v := range ring {
+		if vertices[v].X > m {
+			m = vertices[v].X
+		}
+	}
+	return m
+}
+
+// bridgeHole joins a hole to the outer ring with a pair of coincident edges
+// from the hole's right most vertex to a visible outer vertex.
+func bridgeHole(vertices []Point, outer, hole []uint32) []uint32 {
+	m := 0
+	for i, v := range hole {
+		if vertices[v].X > vertices[hole[m]].X {
+			m = i
+		}
+	}
+	M := vertices[hole[m]]
+
+	// Cast a ray to the right and find the nearest outer edge it hits.
+	edge := -1
+	hitX := float32(math.MaxFloat32)
+	for i := range outer {
+		a := vertices[outer[i]]
+		b := vertices[outer[(i+1)%len(outer)]]
+		if a.Y == b.Y || (a.Y-M.Y)*(b.Y-M.Y) > 0 {
+			continue
+		}
+		x := a.X + (M.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y)
+		if x >= M.X && x < hitX {
+			hitX = x
+			edge = i
+		}
+	}
+
+	if edge < 0 {
+		// The hole isn't inside the ring, leave it out.
+		return outer
+	}
+
+	// Start with the hit edge's right most end point.
+	best := edge
+	if vertices[outer[(edge+1)%len(outer)]].X > vertices[outer[edge]].X {
+		best = (edge + 1) % len(outer)
+	}
+	I := Point{X: hitX, Y: M.Y}
+	P := vertices[outer[best]]
+
+	// An outer vertex inside triangle M,I,P may block the view of P, the one
+	// closest in angle to the ray is visible.
+	if P != I {
+		bestTan := float32(math.MaxFloat32)
+		if P.X != M.X {
+			bestTan = float32(math.Abs(float64((P.Y - M.Y) / (P.X - M.X))))
+		}
+
+		for i, v := range outer {
+			p := vertices[v]
+			if i == best || p.X <= M.X || !pointInTriangle(M, I, P, p) {
+				continue
+			}
+			tan := float32(math.Abs(float64((p.Y - M.Y) / (p.X - M.X))))
+			if tan < bestTan || (tan == bestTan && p.X > vertices[outer[best]].X) {
+				bestTan = tan
+				best = i
+			}
+		}
+	}
+
+	// outer[..best], hole[m..], hole[..m] (M again), outer[best..] (P again)
+	merged := make([]uint32, 0, len(outer)+len(hole)+2)
+	merged = append(merged, outer[:best+1]...)
+	merged = append(merged, hole[m:]...)
+	merged = append(merged, hole[:m+1]...)
+	merged = append(merged, outer[best:]...)
+
+	return merged
+}
+
+// earClip triangulates a counter-clockwise ring.
+func earClip(vertices []Point, ring []uint32) []uint32 {
+	indices := []uint32{}
+	ring = append([]uint32{}, ring...)
+
+	for len(ring) > 3 {
+		clipped := false
+
+		for i := range ring {
+			prev := ring[(i+len(ring)-1)%len(ring)]
+			cur := ring[i]
+			next := ring[(i+1)%len(ring)]
+
+			a, b, c := vertices[prev], vertices[cur], vertices[next]
+			cross := orient(a, b, c)
+
+			if cross == 0 {
+				// Collinear or a bridge spike, it adds no area.
+				ring = append(ring[:i], ring[i+1:]...)
+				clipped = true
+				break
+			}
+
+			if cross < 0 || !isEar(vertices, ring, a, b, c) {
+				continue
+			}
+
+			indices = append(indices, prev, cur, next)
+			ring = append(ring[:i], ring[i+1:]...)
+			clipped = true
+			break
+		}
+
+		if !clipped {
+			// Self intersecting or numerically degenerate, clip anyway so
+			// the loop terminates.
+			indices = append(indices, ring[len(ring)-1], ring[0], ring[1])
+			ring = ring[1:]
+		}
+	}
+
+	if len(ring) == 3 && orient(vertices[ring[0]], vertices[ring[1]], vertices[ring[2]]) > 0 {
+		indices = append(indices, ring...)
+	}
+
+	return indices
+}
+
+// isEar reports whether no other ring vertex lies inside triangle a,b,c.
+func isEar(vertices []Point, ring []uint32, a, b, c Point) bool {
+	for _, v := range ring {
+		p := vertices[v]
+		if p == a || p == b || p == c {
+			continue
+		}
+		if pointInTriangle(a, b, c, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// orient is positive when a,b,c turn counter-clockwise.
+func orient(a, b, c Point) float32 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+// pointInTriangle includes points on the edges regardless of winding.
+func pointInTriangle(a, b, c, p Point) bool {
+	d1 := orient(a, b, p)
+	d2 := orient(b, c, p)
+	d3 := orient(c, a, p)
+
+	hasNeg := d1 < 0 || d2 < 0 || d3 < 0
+	hasPos := d1 > 0 || d2 > 0 || d3 > 0
+
+	return !(hasNeg && hasPos)
+}
@@ -0,0 +1,185 @@
+package fonts
+
+import (
+	"encoding/base64"
+	"image"
+	"image/draw"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/wdevore/ranger/config"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Glyph pages never exceed this dimension, extra glyphs go on more pages.
+const maxPageSize = 4096
+
+// Empty texels between glyphs to prevent bleeding when filtering.
+const glyphPadding = 2
+
+// Neuropol returns the embedded Neuropol TTF.
+func Neuropol() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(FontNeuropol)
+}
+
+// LoadFont reads the TTF described by the font settings. If the settings
+// don't name a font then the embedded Neuropol font is returned.
+func LoadFont(settings *config.FontObj) ([]byte, error) {
+	if settings.Name == "" {
+		return Neuropol()
+	}
+
+	return ioutil.ReadFile(filepath.Join(settings.Path, settings.Name))
+}
+
+// NewTrueTypeAtlasFromSettings rasterises the font described by the font
+// settings at settings.Size using settings.CharsFromSet.
+func NewTrueTypeAtlasFromSettings(settings *config.FontObj) (*GlyphAtlas, error) {
+	ttf, err := LoadFont(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	ga, err := NewTrueTypeAtlas(settings.Name, ttf, float32(settings.Size), CharSet(settings.CharsFromSet))
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.Scale > 0.0 {
+		ga.DefaultScale = settings.Scale
+	}
+
+	return ga, nil
+}
+
+// rasterGlyph is a glyph's coverage mask before it is packed.
+type rasterGlyph struct {
+	glyph  *Glyph
+	mask   *image.Alpha
+	bounds image.Rectangle
+}
+
+// NewTrueTypeAtlas rasterises runes of a TTF at size pixels into a GlyphAtlas.
+// Pages are white with the glyph coverage in the alpha channel.
+func NewTrueTypeAtlas(name string, ttf []byte, size float32, runes []rune) (*GlyphAtlas, error) {
+	f, err := opentype.Parse(ttf)
+	if err != nil {
+		return nil, err
+	}
+
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    float64(size),
+		DPI:     72.0,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The face isn't closed, the atlas kerns with it.
+
+	ga := NewGlyphAtlas(name, size)
+
+	metrics := face.Metrics()
+	ga.Ascent = fixedToFloat(metrics.Ascent)
+	ga.Descent = fixedToFloat(metrics.Descent)
+	ga.LineHeight = fixedToFloat(metrics.Height)
+
+	rasters := []*rasterGlyph{}
+	area := 0
+
+	for _, r := range runes {
+		// The dot is at the origin so dr is relative to the baseline
+		// with +Y downwards.
+		dr, mask, maskp, advance, ok := face.Glyph(fixed.Point26_6{}, r)
+		if !ok {
+			continue
+		}
+
+		g := &Glyph{
+			Rune:     r,
+			Advance:  fixedToFloat(advance),
+			BearingX: float32(dr.Min.X),
+			BearingY: float32(-dr.Min.Y),
+		}
+		ga.Glyphs[r] = g
+
+		if dr.Empty() {
+			// For example, a space.
+			continue
+		}
+
+		// The face reuses its mask buffer so it must be copied.
+		copied := image.NewAlpha(image.Rect(0, 0, dr.Dx(), dr.Dy()))
+		draw.Draw(copied, copied.Bounds(), mask, maskp, draw.Src)
+
+		rasters = append(rasters, &rasterGlyph{glyph: g, mask: copied, bounds: dr})
+		area += (dr.Dx() + glyphPadding) * (dr.Dy() + glyphPadding)
+	}
+
+	ga.packGlyphs(rasters, pageSizeFor(area))
+	ga.kernFace(face, 1.0)
+
+	return ga, nil
+}
+
+// kernFace kerns the atlas' glyphs with the face's kerning scaled by
+// 'scale'. Pairs are looked up as text uses them, looking up every pair
+// of a large rune set up front is far too slow.
+func (ga *GlyphAtlas) kernFace(face font.Face, scale float32) {
+	ga.kernSource = func(left, right rune) float32 {
+		if ga.Glyphs[left] == nil || ga.Glyphs[right] == nil {
+			return 0.0
+		}
+		return fixedToFloat(face.Kern(left, right)) * scale
+	}
+}
+
+// packGlyphs draws each glyph's mask into pages as white with alpha coverage.
+func (ga *GlyphAtlas) packGlyphs(rasters []*rasterGlyph, pageSize int) {
+	var page *image.NRGBA
+	var packer *shelfPacker
+
+	newPage := func() {
+		page = image.NewNRGBA(image.Rect(0, 0, pageSize, pageSize))
+		ga.Pages = append(ga.Pages, page)
+		packer = newShelfPacker(pageSize, pageSize, glyphPadding)
+	}
+
+	newPage()
+
+	for _, rg := range rasters {
+		size := rg.bounds.Size()
+
+		pos, ok := packer.place(size.X, size.Y)
+		if !ok {
+			newPage()
+			pos, ok = packer.place(size.X, size.Y)
+			if !ok {
+				// Larger than a whole page, skip it.
+				continue
+			}
+		}
+
+		region := image.Rectangle{Min: pos, Max: pos.Add(size)}
+		draw.DrawMask(page, region, image.White, image.Point{}, rg.mask, image.Point{}, draw.Src)
+
+		rg.glyph.Page = len(ga.Pages) - 1
+		rg.glyph.Region = region
+	}
+}
+
+// pageSizeFor returns a power of two page size with room for 'area' texels.
+func pageSizeFor(area int) int {
+	size := 64
+	// Shelf packing wastes space so leave some slack.
+	for size < maxPageSize && size*size < area*5/4 {
+		size *= 2
+	}
+	return size
+}
+
+func fixedToFloat(v fixed.Int26_6) float32 {
+	return float32(v) / 64.0
+}
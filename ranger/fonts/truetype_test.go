@@ -0,0 +1,110 @@
+package fonts
+
+import (
+	"testing"
+)
+
+func newTestAtlas(t *testing.T) *GlyphAtlas {
+	ttf, err := Neuropol()
+	if err != nil {
+		t.Fatalf("Expected embedded font to decode, got: %v", err)
+	}
+
+	ga, err := NewTrueTypeAtlas("neuropol", ttf, 24.0, CharSet(128))
+	if err != nil {
+		t.Fatalf("Expected atlas to build, got: %v", err)
+	}
+
+	return ga
+}
+
+func Test_TrueType_Atlas(t *testing.T) {
+	ga := newTestAtlas(t)
+
+	if len(ga.Pages) != 1 {
+		t.Errorf("Expected 1 page, got: %d", len(ga.Pages))
+	}
+
+	if ga.LineHeight <= 0.0 {
+		t.Errorf("Expected a positive line height, got: %f", ga.LineHeight)
+	}
+
+	g := ga.Glyph('A')
+	if g == nil || g.Region.Empty() {
+		t.Fatalf("Expected a rasterised 'A'")
+	}
+
+	if g.Advance <= 0.0 {
+		t.Errorf("Expected a positive advance, got: %f", g.Advance)
+	}
+
+	// A space has an advance but nothing to draw.
+	space := ga.Glyph(' ')
+	if space == nil || !space.Region.Empty() {
+		t.Errorf("Expected an empty space glyph")
+	}
+
+	// Regions must not overlap.
+	for ra, a := range ga.Glyphs {
+		for rb, b := range ga.Glyphs {
+			if ra != rb && a.Page == b.Page && a.Region.Overlaps(b.Region) {
+				t.Errorf("Expected no overlap, got: '%c' and '%c'", ra, rb)
+			}
+		}
+	}
+}
+
+func Test_BuildQuads_Alignment(t *testing.T) {
+	ga := newTestAtlas(t)
+
+	width := MeasureLine(ga, "Hi")
+	if width <= 0.0 {
+		t.Fatalf("Expected a positive width, got: %f", width)
+	}
+
+	left := BuildQuads(ga, "Hi", AlignLeft)
+	right := BuildQuads(ga, "Hi", AlignRight)
+	center := BuildQuads(ga, "Hi", AlignCenter)
+
+	if len(left) != 2 || len(right) != 2 || len(center) != 2 {
+		t.Fatalf("Expected 2 quads each, got: %d %d %d", len(left), len(right), len(center))
+	}
+
+	if d := left[0].X0 - right[0].X0; d != width {
+		t.Errorf("Expected right alignment to shift by %f, got: %f", width, d)
+	}
+
+	if d := left[0].X0 - center[0].X0; d != width/2.0 {
+		t.Errorf("Expected center alignment to shift by %f, got: %f", width/2.0, d)
+	}
+
+	lines := BuildQuads(ga, "H\nH", AlignLeft)
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 quads, got: %d", len(lines))
+	}
+
+	if d := lines[0].Y0 - lines[1].Y0; d != ga.LineHeight {
+		t.Errorf("Expected second line %f lower, got: %f", ga.LineHeight, d)
+	}
+}
+
+func Test_GlyphAtlas_KernSource(t *testing.T) {
+	ga := NewGlyphAtlas("test", 16.0)
+
+	lookups := 0
+	ga.kernSource = func(left, right rune) float32 {
+		lookups++
+		return -1.0
+	}
+
+	ga.Kern('A', 'V')
+	if k := ga.Kern('A', 'V'); k != -1.0 || lookups != 1 {
+		t.Errorf("Expected one lookup of -1, got: %d of %f", lookups, k)
+	}
+
+	// Set pairs override the source, even zeros.
+	ga.SetKern('A', 'W', 0.0)
+	if k := ga.Kern('A', 'W'); k != 0.0 || lookups != 1 {
+		t.Errorf("Expected the set 0 without a lookup, got: %d of %f", lookups, k)
+	}
+}
@@ -0,0 +1,176 @@
+package fonts
+
+import (
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/wdevore/ranger/config"
+)
+
+// VectorGlyph is a glyph's filled outline as triangles.
+type VectorGlyph struct {
+	Rune rune
+
+	// Vertices are x,y pairs in ems with the pen at the origin on the
+	// baseline and +Y upwards.
+	Vertices []float32
+	// Indices form triangles. Empty for blank glyphs, for example, a space.
+	Indices []uint32
+
+	Advance float32
+}
+
+// VectorFont holds tessellated glyph outlines. Metrics are in ems, so
+// text is 1 unit high until it is scaled.
+type VectorFont struct {
+	Name   string
+	Glyphs map[rune]*VectorGlyph
+
+	LineHeight float32
+	Ascent     float32
+	Descent    float32
+
+	// kernSource looks up kerning on demand, results are kept in
+	// kernCache.
+	kernSource func(left, right rune) float32
+	kernCache  map[[2]rune]float32
+
+	// Fallback is used for runes that aren't in the font.
+	Fallback rune
+}
+
+// NewVectorFontFromSettings tessellates the font described by the font
+// settings using settings.CharsFromSet.
+func NewVectorFontFromSettings(settings *config.FontObj) (*VectorFont, error) {
+	ttf, err := LoadFont(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewVectorFont(settings.Name, ttf, CharSet(settings.CharsFromSet))
+}
+
+// NewVectorFont flattens and triangulates the outlines of runes of a TTF.
+func NewVectorFont(name string, ttf []byte, runes []rune) (*VectorFont, error) {
+	f, err := sfnt.Parse(ttf)
+	if err != nil {
+		return nil, err
+	}
+
+	vf := new(VectorFont)
+	vf.Name = name
+	vf.Glyphs = make(map[rune]*VectorGlyph)
+	vf.kernCache = make(map[[2]rune]float32)
+	vf.Fallback = '?'
+
+	var buf sfnt.Buffer
+
+	// Loading at UnitsPerEm keeps the font's full precision.
+	ppem := fixed.I(int(f.UnitsPerEm()))
+	toEms := func(v fixed.Int26_6) float32 {
+		return float32(v) / float32(ppem)
+	}
+
+	metrics, err := f.Metrics(&buf, ppem, font.HintingNone)
+	if err != nil {
+		return nil, err
+	}
+	vf.Ascent = toEms(metrics.Ascent)
+	vf.Descent = toEms(metrics.Descent)
+	vf.LineHeight = toEms(metrics.Height)
+
+	indices := map[rune]sfnt.GlyphIndex{}
+
+	for _, r := range runes {
+		index, err := f.GlyphIndex(&buf, r)
+		if err != nil || index == 0 {
+			// Not in the font.
+			continue
+		}
+		indices[r] = index
+
+		advance, err := f.GlyphAdvance(&buf, index, ppem, font.HintingNone)
+		if err != nil {
+			return nil, err
+		}
+
+		contours, err := glyphContours(f, &buf, index, ppem)
+		if err != nil {
+			return nil, err
+		}
+
+		vertices, triangles := Triangulate(contours)
+
+		g := &VectorGlyph{Rune: r, Advance: toEms(advance), Indices: triangles}
+		for _, p := range vertices {
+			g.Vertices = append(g.Vertices, p.X, p.Y)
+		}
+
+		vf.Glyphs[r] = g
+	}
+
+	// Pairs are looked up as text uses them, looking up every pair of a
+	// large rune set up front is far too slow.
+	var kernBuf sfnt.Buffer
+	vf.kernSource = func(left, right rune) float32 {
+		li, lok := indices[left]
+		ri, rok := indices[right]
+		if !lok || !rok {
+			return 0.0
+		}
+
+		k, err := f.Kern(&kernBuf, li, ri, ppem, font.HintingNone)
+		if err != nil {
+			return 0.0
+		}
+		return toEms(k)
+	}
+
+	return vf, nil
+}
+
+// Glyph returns the glyph for a rune, the Fallback glyph or nil.
+func (vf *VectorFont) Glyph(r rune) *VectorGlyph {
+	if g, ok := vf.Glyphs[r]; ok {
+		return g
+	}
+
+	return vf.Glyphs[vf.Fallback]
+}
+
+// Kern returns the adjustment to the advance between two runes.
+func (vf *VectorFont) Kern(left, right rune) float32 {
+	pair := [2]rune{left, right}
+	k, ok := vf.kernCache[pair]
+	if !ok && vf.kernSource != nil {
+		k = vf.kernSource(left, right)
+		vf.kernCache[pair] = k
+	}
+	return k
+}
+
+// Layout positions each glyph of the text in ems.
+func (vf *VectorFont) Layout(text string, options LayoutOptions) *TextLayout {
+	return LayoutText(vf, text, options)
+}
+
+// MeasureLine returns the advance width of a single line in ems.
+func (vf *VectorFont) MeasureLine(line string) float32 {
+	return MeasureWidth(vf, line)
+}
+
+// GlyphAdvance returns how far the pen moves after a rune or false if the
+// rune can't be drawn.
+func (vf *VectorFont) GlyphAdvance(r rune) (float32, bool) {
+	g := vf.Glyph(r)
+	if g == nil {
+		return 0.0, false
+	}
+	return g.Advance, true
+}
+
+// LineMetrics returns the ascent, descent and line height.
+func (vf *VectorFont) LineMetrics() (ascent, descent, lineHeight float32) {
+	return vf.Ascent, vf.Descent, vf.LineHeight
+}
@@ -0,0 +1,198 @@
+package fonts
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// triangleArea sums the signed areas of the triangles.
+func triangleArea(vertices []Point, indices []uint32) float32 {
+	area := float32(0.0)
+	for i := 0; i+2 < len(indices); i += 3 {
+		area += orient(vertices[indices[i]], vertices[indices[i+1]], vertices[indices[i+2]]) / 2.0
+	}
+	return area
+}
+
+func Test_Triangulate_Square_With_Hole(t *testing.T) {
+	outer := Contour{{0, 0}, {4, 0}, {4, 4}, {0, 4}}
+	// Holes are usually wound opposite to the outer contour.
+	hole := Contour{{1, 1}, {1, 3}, {3, 3}, {3, 1}}
+
+	vertices, indices := Triangulate([]Contour{outer, hole})
+
+	if len(indices)%3 != 0 {
+		t.Fatalf("Expected whole triangles, got: %d indices", len(indices))
+	}
+
+	area := triangleArea(vertices, indices)
+	if area != 12.0 {
+		t.Errorf("Expected area 12, got: %f", area)
+	}
+
+	// No triangle may cover the hole's center.
+	center := Point{2, 2}
+	for i := 0; i < len(indices); i += 3 {
+		a, b, c := vertices[indices[i]], vertices[indices[i+1]], vertices[indices[i+2]]
+		if orient(a, b, c) != 0 && pointInTriangle(a, b, c, center) {
+			t.Errorf("Expected hole to be empty, got triangle: %v %v %v", a, b, c)
+		}
+	}
+}
+
+func Test_Triangulate_Clockwise(t *testing.T) {
+	// An "L" wound clockwise.
+	l := Contour{{0, 0}, {0, 3}, {1, 3}, {1, 1}, {2, 1}, {2, 0}}
+
+	vertices, indices := Triangulate([]Contour{l})
+
+	if len(indices) != 12 {
+		t.Errorf("Expected 4 triangles, got: %d indices", len(indices))
+	}
+
+	area := triangleArea(vertices, indices)
+	if area != 4.0 {
+		t.Errorf("Expected area 4, got: %f", area)
+	}
+}
+
+func Test_Vector_Font(t *testing.T) {
+	ttf, err := Neuropol()
+	if err != nil {
+		t.Fatalf("Expected embedded font to decode, got: %v", err)
+	}
+
+	vf, err := NewVectorFont("neuropol", ttf, CharSet(128))
+	if err != nil {
+		t.Fatalf("Expected vector font to build, got: %v", err)
+	}
+
+	if vf.LineHeight <= 0.0 || vf.LineHeight > 2.0 {
+		t.Errorf("Expected line height in ems, got: %f", vf.LineHeight)
+	}
+
+	if g := vf.Glyph(' '); g == nil || len(g.Indices) != 0 {
+		t.Errorf("Expected an empty space glyph")
+	}
+
+	// The tessellation must cover the same area as the outlines.
+	for _, r := range "AOB8%" {
+		g := vf.Glyph(r)
+		if g == nil || len(g.Indices) == 0 {
+			t.Errorf("Expected triangles for '%c'", r)
+			continue
+		}
+
+		vertices := []Point{}
+		for i := 0; i < len(g.Vertices); i += 2 {
+			vertices = append(vertices, Point{g.Vertices[i], g.Vertices[i+1]})
+		}
+
+		area := triangleArea(vertices, g.Indices)
+		if area <= 0.0 {
+			t.Errorf("Expected counter-clockwise triangles for '%c', got area: %f", r, area)
+		}
+
+		// Even-odd area of the outlines.
+		contours := contoursOf(t, r)
+		expected := float32(0.0)
+		for i, c := range contours {
+			depth := 0
+			for j, other := range contours {
+				if i != j && other.Contains(c[0]) {
+					depth++
+				}
+			}
+			a := float32(math.Abs(float64(c.Area())))
+			if depth%2 == 0 {
+				expected += a
+			} else {
+				expected -= a
+			}
+		}
+
+		if math.Abs(float64(area-expected)) > 1e-3 {
+			t.Errorf("Expected area %f for '%c', got: %f", expected, r, area)
+		}
+	}
+
+	placements := vf.Layout("AB", LayoutOptions{Align: AlignRight}).Glyphs
+	if len(placements) != 2 {
+		t.Fatalf("Expected 2 placements, got: %d", len(placements))
+	}
+	if placements[0].X != -vf.MeasureLine("AB") {
+		t.Errorf("Expected right aligned start %f, got: %f", -vf.MeasureLine("AB"), placements[0].X)
+	}
+}
+
+func contoursOf(t *testing.T, r rune) []Contour {
+	ttf, _ := Neuropol()
+	f, err := sfnt.Parse(ttf)
+	if err != nil {
+		t.Fatalf("Expected font to parse, got: %v", err)
+	}
+
+	var buf sfnt.Buffer
+	index, _ := f.GlyphIndex(&buf, r)
+	contours, err := glyphContours(f, &buf, index, fixed.I(int(f.UnitsPerEm())))
+	if err != nil {
+		t.Fatalf("Expected contours for '%c', got: %v", r, err)
+	}
+
+	return contours
+}
+
+func Test_Vector_Font_Kerning(t *testing.T) {
+	ttf, err := Neuropol()
+	if err != nil {
+		t.Fatalf("Expected embedded font to decode, got: %v", err)
+	}
+
+	vf, err := NewVectorFont("neuropol", ttf, CharSet(128))
+	if err != nil {
+		t.Fatalf("Expected vector font to build, got: %v", err)
+	}
+
+	f, err := sfnt.Parse(ttf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf sfnt.Buffer
+	ppem := fixed.I(int(f.UnitsPerEm()))
+
+	// Pairs are looked up on demand but must match the font's.
+	pairs := 0
+	for _, left := range CharSet(128) {
+		li, _ := f.GlyphIndex(&buf, left)
+		for _, right := range CharSet(128) {
+			ri, _ := f.GlyphIndex(&buf, right)
+			if li == 0 || ri == 0 {
+				continue
+			}
+
+			k, err := f.Kern(&buf, li, ri, ppem, font.HintingNone)
+			if err != nil || k == 0 {
+				continue
+			}
+			pairs++
+
+			expected := float32(k) / float32(ppem)
+			if got := vf.Kern(left, right); got != expected {
+				t.Errorf("Expected '%c' '%c' kerning %f, got: %f", left, right, expected, got)
+			}
+		}
+	}
+
+	if pairs == 0 {
+		t.Error("Expected the font to have kerning pairs")
+	}
+
+	if k := vf.Kern('A', '一'); k != 0.0 {
+		t.Errorf("Expected no kerning with a missing rune, got: %f", k)
+	}
+}
@@ -22,7 +22,9 @@ func NewCamera() *Camera {
 	return c
 }
 
-// SetProjection sets orthographic frustum dimensions
+// SetProjection sets orthographic frustum dimensions. The projection has
+// its origin at the bottom-left and, like Centered, is adjusted for aspect
+// ratio so it's in virtual units.
 func (c *Camera) SetProjection(ratioCorrection, bottom, left, top, right float32) {
 	c.ratioCorrection = ratioCorrection
 
@@ -33,7 +35,14 @@ func (c *Camera) SetProjection(ratioCorrection, bottom, left, top, right float32
 	c.Width = right - left
 	c.Height = top - bottom
 
-	c.Matrix.SetToOrtho(0.0, 0.0, c.Width, c.Height, 0.1, 100.0)
+	// SetToOrtho takes left, right, bottom, top.
+	// Adjust for aspect ratio such that the projection is in virtual units.
+	c.Matrix.SetToOrtho(0.0, c.Width/c.ratioCorrection, 0.0, c.Height/c.ratioCorrection, 0.1, 100.0)
+}
+
+// RatioCorrection returns the device to virtual aspect ratio correction
+func (c *Camera) RatioCorrection() float32 {
+	return c.ratioCorrection
 }
 
 // Centered centers the projection and adjusted for aspect ratio
@@ -0,0 +1,282 @@
+// Package graphics provides camera control
+package graphics
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/wdevore/ranger/rmath"
+)
+
+// CameraTarget is anything a CameraController can follow, for example, a Node.
+type CameraTarget interface {
+	Position() *rmath.Vector3
+}
+
+// CameraController moves the View's focus around the world. It can follow a
+// target with a dead-zone and smoothing, clamp to world bounds, zoom, rotate
+// and shake.
+type CameraController struct {
+	// The world-space point the camera is looking at.
+	position rmath.Vector3
+
+	target CameraTarget
+
+	// The target can move freely within the dead-zone (centered on the
+	// camera's position) without the camera moving.
+	deadZoneWidth, deadZoneHeight float32
+
+	// Smoothing controls how quickly the camera catches up to the target.
+	// 0.0 = snaps instantly, larger values catch up faster.
+	Smoothing float32
+
+	// World bounds the visible area is clamped to. nil = unbounded.
+	bounds *rmath.Rectangle
+
+	// Visible world dimensions at a zoom of 1.0, typically the VirtualRes.
+	viewWidth, viewHeight float32
+
+	zoom     float32
+	rotation float32
+
+	// ---------------------------------------------------------------------
+	// Shake
+	// ---------------------------------------------------------------------
+	// trauma is in the range [0.0, 1.0]. The shake applied is trauma^2.
+	trauma float32
+	// TraumaDecay is how much trauma is removed per second.
+	TraumaDecay float32
+	// MaxShakeOffset is the largest positional offset (world units).
+	MaxShakeOffset float32
+	// MaxShakeAngle is the largest rotational offset (radians).
+	MaxShakeAngle float32
+
+	shakeOffset rmath.Vector3
+	shakeAngle  float32
+
+	random *rand.Rand
+
+	// Matrix is the world-to-view transform computed by Update.
+	Matrix rmath.Matrix4
+}
+
+// NewCameraController constructs a CameraController whose visible area,
+// at a zoom of 1.0, is width x height.
+func NewCameraController(width, height float32) *CameraController {
+	cc := new(CameraController)
+	cc.viewWidth = width
+	cc.viewHeight = height
+	cc.zoom = 1.0
+	cc.TraumaDecay = 1.0
+	cc.MaxShakeOffset = 10.0
+	cc.MaxShakeAngle = rmath.ToRadians(5.0)
+	cc.random = rand.New(rand.NewSource(1))
+	cc.Matrix.ToIdentity()
+	return cc
+}
+
+// ---------------------------------------------------------------------
+// Properties
+// ---------------------------------------------------------------------
+
+// Follow sets the target to follow. nil stops following.
+func (cc *CameraController) Follow(target CameraTarget) {
+	cc.target = target
+}
+
+// SetDeadZone sets the dimensions of the area, centered on the camera, the
+// target can move within without the camera moving.
+func (cc *CameraController) SetDeadZone(width, height float32) {
+	cc.deadZoneWidth = width
+	cc.deadZoneHeight = height
+}
+
+// SetBounds sets the world bounds the visible area is clamped to.
+// nil removes clamping.
+func (cc *CameraController) SetBounds(bounds *rmath.Rectangle) {
+	cc.bounds = bounds
+}
+
+// SetPosition moves the camera's focus immediately.
+func (cc *CameraController) SetPosition(x, y float32) {
+	cc.position.Set2Components(x, y)
+}
+
+// Position returns the camera's focus in world-space.
+func (cc *CameraController) Position() *rmath.Vector3 {
+	return &cc.position
+}
+
+// SetZoom sets the zoom factor where values > 1.0 zoom in.
+func (cc *CameraController) SetZoom(zoom float32) {
+	if zoom <= 0.0 {
+		return
+	}
+	cc.zoom = zoom
+}
+
+// Zoom returns the zoom factor
+func (cc *CameraController) Zoom() float32 {
+	return cc.zoom
+}
+
+// ZoomBy scales the current zoom factor
+func (cc *CameraController) ZoomBy(factor float32) {
+	cc.SetZoom(cc.zoom * factor)
+}
+
+// SetRotation sets the camera's rotation in radians
+func (cc *CameraController) SetRotation(angle float32) {
+	cc.rotation = angle
+}
+
+// Rotation returns the camera's rotation in radians
+func (cc *CameraController) Rotation() float32 {
+	return cc.rotation
+}
+
+// ---------------------------------------------------------------------
+// Shake
+// ---------------------------------------------------------------------
+
+// AddTrauma increases the shake. The trauma is clamped to [0.0, 1.0].
+func (cc *CameraController) AddTrauma(amount float32) {
+	cc.trauma = clamp(cc.trauma+amount, 0.0, 1.0)
+}
+
+// Trauma returns the current trauma
+func (cc *CameraController) Trauma() float32 {
+	return cc.trauma
+}
+
+// ---------------------------------------------------------------------
+// Update
+// ---------------------------------------------------------------------
+
+// Update advances following, clamping and shake by dt seconds and
+// recomputes Matrix.
+func (cc *CameraController) Update(dt float32) {
+	if cc.target != nil {
+		cc.follow(dt)
+	}
+
+	cc.clampToBounds()
+
+	cc.shake(dt)
+
+	cc.calcTransform()
+}
+
+// Apply updates the View's matrix with this controller's transform.
+func (cc *CameraController) Apply(view *View) {
+	view.ApplyCamera(&cc.Matrix)
+}
+
+func (cc *CameraController) follow(dt float32) {
+	tp := cc.target.Position()
+
+	hw := cc.deadZoneWidth / 2.0
+	hh := cc.deadZoneHeight / 2.0
+
+	desiredX := cc.position.X
+	desiredY := cc.position.Y
+
+	// Only move once the target pushes against an edge of the dead-zone.
+	dx := tp.X - cc.position.X
+	if dx > hw {
+		desiredX = tp.X - hw
+	} else if dx < -hw {
+		desiredX = tp.X + hw
+	}
+
+	dy := tp.Y - cc.position.Y
+	if dy > hh {
+		desiredY = tp.Y - hh
+	} else if dy < -hh {
+		desiredY = tp.Y + hh
+	}
+
+	// Frame rate independent exponential smoothing.
+	t := float32(1.0)
+	if cc.Smoothing > 0.0 {
+		t = 1.0 - float32(math.Exp(float64(-cc.Smoothing*dt)))
+	}
+
+	cc.position.X += (desiredX - cc.position.X) * t
+	cc.position.Y += (desiredY - cc.position.Y) * t
+}
+
+func (cc *CameraController) clampToBounds() {
+	if cc.bounds == nil {
+		return
+	}
+
+	b := cc.bounds
+
+	// Half of the visible area in world units.
+	hw := cc.viewWidth / 2.0 / cc.zoom
+	hh := cc.viewHeight / 2.0 / cc.zoom
+
+	cc.position.X = clampAxis(cc.position.X, rmath.Min32(b.Left, b.Right), rmath.Max32(b.Left, b.Right), hw)
+	cc.position.Y = clampAxis(cc.position.Y, rmath.Min32(b.Top, b.Bottom), rmath.Max32(b.Top, b.Bottom), hh)
+}
+
+func (cc *CameraController) shake(dt float32) {
+	if cc.trauma <= 0.0 {
+		cc.shakeOffset.Set2Components(0.0, 0.0)
+		cc.shakeAngle = 0.0
+		return
+	}
+
+	s := cc.trauma * cc.trauma
+
+	cc.shakeOffset.Set2Components(
+		cc.MaxShakeOffset*s*cc.randomUnit(),
+		cc.MaxShakeOffset*s*cc.randomUnit())
+	cc.shakeAngle = cc.MaxShakeAngle * s * cc.randomUnit()
+
+	cc.trauma = clamp(cc.trauma-cc.TraumaDecay*dt, 0.0, 1.0)
+}
+
+// randomUnit returns a value in the range [-1.0, 1.0]
+func (cc *CameraController) randomUnit() float32 {
+	return cc.random.Float32()*2.0 - 1.0
+}
+
+// calcTransform builds: [rotate] x [scale] x [translate]
+func (cc *CameraController) calcTransform() {
+	var translate, scale, rotate, ts rmath.Matrix4
+
+	translate.SetTranslate3Comp(
+		-(cc.position.X + cc.shakeOffset.X),
+		-(cc.position.Y + cc.shakeOffset.Y),
+		0.0)
+	scale.SetScale3Comp(cc.zoom, cc.zoom, 1.0)
+	rotate.SetRotation(-(cc.rotation + cc.shakeAngle))
+
+	rmath.Multiply(&scale, &translate, &ts)
+	rmath.Multiply(&rotate, &ts, &cc.Matrix)
+}
+
+// clampAxis keeps a visible span of 2*half centered on v within [min, max].
+// If the span is larger than the bounds then v is centered.
+func clampAxis(v, min, max, half float32) float32 {
+	lo := min + half
+	hi := max - half
+
+	if lo > hi {
+		return (min + max) / 2.0
+	}
+
+	return clamp(v, lo, hi)
+}
+
+func clamp(v, min, max float32) float32 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
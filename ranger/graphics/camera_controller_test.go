@@ -0,0 +1,138 @@
+package graphics
+
+import (
+	"testing"
+
+	"github.com/wdevore/ranger/rmath"
+)
+
+type testTarget struct {
+	position rmath.Vector3
+}
+
+func (tt *testTarget) Position() *rmath.Vector3 {
+	return &tt.position
+}
+
+func Test_CameraController_FollowSnaps(t *testing.T) {
+	cc := NewCameraController(100.0, 100.0)
+	target := &testTarget{}
+	target.position.Set2Components(30.0, -20.0)
+
+	cc.Follow(target)
+	cc.Update(1.0 / 60.0)
+
+	p := cc.Position()
+	if !rmath.IsEqual(p.X, 30.0) || !rmath.IsEqual(p.Y, -20.0) {
+		t.Errorf("Expected camera at <30, -20>, got: %s", p)
+	}
+}
+
+func Test_CameraController_DeadZone(t *testing.T) {
+	cc := NewCameraController(100.0, 100.0)
+	cc.SetDeadZone(20.0, 20.0)
+	target := &testTarget{}
+	cc.Follow(target)
+
+	// Inside the dead-zone the camera doesn't move.
+	target.position.Set2Components(8.0, -8.0)
+	cc.Update(1.0 / 60.0)
+
+	p := cc.Position()
+	if !rmath.IsEqual(p.X, 0.0) || !rmath.IsEqual(p.Y, 0.0) {
+		t.Errorf("Expected camera at <0, 0>, got: %s", p)
+	}
+
+	// Pushing past the edge drags the camera along.
+	target.position.Set2Components(25.0, 0.0)
+	cc.Update(1.0 / 60.0)
+
+	if !rmath.IsEqual(p.X, 15.0) {
+		t.Errorf("Expected camera X = 15.0, got: %f", p.X)
+	}
+}
+
+func Test_CameraController_Smoothing(t *testing.T) {
+	cc := NewCameraController(100.0, 100.0)
+	cc.Smoothing = 5.0
+	target := &testTarget{}
+	target.position.Set2Components(100.0, 0.0)
+	cc.Follow(target)
+
+	cc.Update(1.0 / 60.0)
+
+	p := cc.Position()
+	if p.X <= 0.0 || p.X >= 100.0 {
+		t.Errorf("Expected camera X between 0 and 100, got: %f", p.X)
+	}
+}
+
+func Test_CameraController_ClampToBounds(t *testing.T) {
+	cc := NewCameraController(100.0, 50.0)
+	cc.SetBounds(rmath.NewRectangleByCorners(0.0, 200.0, 400.0, 0.0))
+
+	cc.SetPosition(-100.0, 500.0)
+	cc.Update(1.0 / 60.0)
+
+	p := cc.Position()
+	if !rmath.IsEqual(p.X, 50.0) || !rmath.IsEqual(p.Y, 175.0) {
+		t.Errorf("Expected camera at <50, 175>, got: %s", p)
+	}
+
+	// Zooming out shows more of the world so the clamp tightens.
+	cc.SetZoom(0.5)
+	cc.Update(1.0 / 60.0)
+
+	if !rmath.IsEqual(p.X, 100.0) || !rmath.IsEqual(p.Y, 150.0) {
+		t.Errorf("Expected camera at <100, 150>, got: %s", p)
+	}
+}
+
+func Test_CameraController_Transform(t *testing.T) {
+	cc := NewCameraController(100.0, 100.0)
+	cc.SetPosition(10.0, 20.0)
+	cc.SetZoom(2.0)
+	cc.Update(0.0)
+
+	// The camera's focus maps to the origin of the view.
+	v := rmath.NewVector3With2Components(10.0, 20.0)
+	v.Mul(&cc.Matrix)
+	if !rmath.IsEqual(v.X, 0.0) || !rmath.IsEqual(v.Y, 0.0) {
+		t.Errorf("Expected <0, 0>, got: %s", v)
+	}
+
+	v.Set2Components(11.0, 20.0)
+	v.Mul(&cc.Matrix)
+	if !rmath.IsEqual(v.X, 2.0) {
+		t.Errorf("Expected X = 2.0 at zoom 2, got: %f", v.X)
+	}
+}
+
+func Test_CameraController_TraumaDecays(t *testing.T) {
+	cc := NewCameraController(100.0, 100.0)
+	cc.AddTrauma(2.0)
+
+	if !rmath.IsEqual(cc.Trauma(), 1.0) {
+		t.Errorf("Expected trauma clamped to 1.0, got: %f", cc.Trauma())
+	}
+
+	cc.Update(0.5)
+
+	if !rmath.IsEqual(cc.Trauma(), 0.5) {
+		t.Errorf("Expected trauma = 0.5, got: %f", cc.Trauma())
+	}
+
+	cc.Update(1.0)
+	cc.Update(1.0)
+
+	if cc.Trauma() != 0.0 {
+		t.Errorf("Expected trauma = 0.0, got: %f", cc.Trauma())
+	}
+
+	// Once trauma is gone the transform is shake free.
+	v := rmath.NewVector3With2Components(0.0, 0.0)
+	v.Mul(&cc.Matrix)
+	if !rmath.IsEqual(v.X, 0.0) || !rmath.IsEqual(v.Y, 0.0) {
+		t.Errorf("Expected <0, 0>, got: %s", v)
+	}
+}
@@ -32,3 +32,12 @@ func (rc *RenderContext) SetClearColors(cs *Colors) {
 func (rc *RenderContext) Clear() {
 	gl.Clear(gl.COLOR_BUFFER_BIT)
 }
+
+// ClearRegion clears the color buffer with the given color and then
+// restores the context's clear color. Combined with a scissor only the
+// scissored region is cleared.
+func (rc *RenderContext) ClearRegion(cs *Colors) {
+	gl.ClearColor(cs.R, cs.G, cs.B, cs.A)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+	gl.ClearColor(rc.clearColor.R, rc.clearColor.G, rc.clearColor.B, rc.clearColor.A)
+}
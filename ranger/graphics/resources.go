@@ -0,0 +1,111 @@
+// Package graphics provides GL resource tracking
+package graphics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ResourceKind is the kind of a GL object.
+type ResourceKind int
+
+const (
+	// BufferResource is a VBO or EBO.
+	BufferResource ResourceKind = iota
+	// VertexArrayResource is a VAO.
+	VertexArrayResource
+	// ProgramResource is a linked shader program.
+	ProgramResource
+	// TextureResource is a texture.
+	TextureResource
+)
+
+func (rk ResourceKind) String() string {
+	switch rk {
+	case BufferResource:
+		return "buffer"
+	case VertexArrayResource:
+		return "vertex array"
+	case ProgramResource:
+		return "program"
+	case TextureResource:
+		return "texture"
+	}
+	return "unknown"
+}
+
+type resourceKey struct {
+	kind ResourceKind
+	id   uint32
+}
+
+// ResourceRegistry tracks the GL objects that have been generated and not
+// yet deleted, so leaks can be reported.
+type ResourceRegistry struct {
+	live map[resourceKey]string
+}
+
+// Resources is the registry of the engine's GL context.
+var Resources = NewResourceRegistry()
+
+// NewResourceRegistry creates an empty registry.
+func NewResourceRegistry() *ResourceRegistry {
+	rr := new(ResourceRegistry)
+	rr.live = map[resourceKey]string{}
+	return rr
+}
+
+// Created records a generated object. 'label' describes it in reports,
+// for example, "VBO" or a shader's file names.
+func (rr *ResourceRegistry) Created(kind ResourceKind, id uint32, label string) {
+	rr.live[resourceKey{kind, id}] = label
+}
+
+// Deleted records that an object was deleted.
+func (rr *ResourceRegistry) Deleted(kind ResourceKind, id uint32) {
+	delete(rr.live, resourceKey{kind, id})
+}
+
+// Count returns how many objects of a kind are alive.
+func (rr *ResourceRegistry) Count(kind ResourceKind) int {
+	count := 0
+	for k := range rr.live {
+		if k.kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// Live returns how many objects are alive.
+func (rr *ResourceRegistry) Live() int {
+	return len(rr.live)
+}
+
+// Report describes the live objects, one per line grouped by kind, or
+// returns "" if there are none.
+func (rr *ResourceRegistry) Report() string {
+	if len(rr.live) == 0 {
+		return ""
+	}
+
+	keys := make([]resourceKey, 0, len(rr.live))
+	for k := range rr.live {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].kind != keys[j].kind {
+			return keys[i].kind < keys[j].kind
+		}
+		return keys[i].id < keys[j].id
+	})
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d GL resources were not deleted:", len(keys))
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "\n  %s %d: %s", k.kind, k.id, rr.live[k])
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,57 @@
+package graphics
+
+import (
+	"testing"
+)
+
+func Test_ResourceRegistry_Tracks(t *testing.T) {
+	rr := NewResourceRegistry()
+
+	rr.Created(BufferResource, 1, "VBO")
+	rr.Created(BufferResource, 2, "EBO")
+	rr.Created(VertexArrayResource, 1, "VAO")
+	rr.Created(ProgramResource, 3, "vector.vert, vector.frag")
+
+	if rr.Live() != 4 {
+		t.Errorf("Expected 4 live resources, got: %d", rr.Live())
+	}
+
+	// Ids are only unique per kind.
+	rr.Deleted(BufferResource, 1)
+
+	if rr.Count(BufferResource) != 1 {
+		t.Errorf("Expected 1 live buffer, got: %d", rr.Count(BufferResource))
+	}
+	if rr.Count(VertexArrayResource) != 1 {
+		t.Errorf("Expected 1 live vertex array, got: %d", rr.Count(VertexArrayResource))
+	}
+}
+
+func Test_ResourceRegistry_Report(t *testing.T) {
+	rr := NewResourceRegistry()
+
+	if rr.Report() != "" {
+		t.Errorf("Expected an empty report, got: %s", rr.Report())
+	}
+
+	rr.Created(TextureResource, 5, "Texture")
+	rr.Created(BufferResource, 2, "EBO")
+	rr.Created(BufferResource, 1, "VBO")
+
+	expected := "3 GL resources were not deleted:" +
+		"\n  buffer 1: VBO" +
+		"\n  buffer 2: EBO" +
+		"\n  texture 5: Texture"
+
+	if rr.Report() != expected {
+		t.Errorf("Expected report:\n%s\ngot:\n%s", expected, rr.Report())
+	}
+
+	rr.Deleted(TextureResource, 5)
+	rr.Deleted(BufferResource, 2)
+	rr.Deleted(BufferResource, 1)
+
+	if rr.Report() != "" {
+		t.Errorf("Expected an empty report after deleting, got: %s", rr.Report())
+	}
+}
@@ -0,0 +1,360 @@
+// Package graphics provides a cache of GL state
+package graphics
+
+import (
+	"github.com/go-gl/gl/v4.5-core/gl"
+)
+
+// unknown marks state that must be set the next time it's asked for.
+const unknown = ^uint32(0)
+
+const maxTextureUnits = 32
+
+// StateStats counts the GL state changes made, and the redundant ones
+// skipped, since the stats were last reset.
+type StateStats struct {
+	Programs     int
+	VertexArrays int
+	Buffers      int
+	Blends       int
+	PolygonModes int
+	Viewports    int
+	Scissors     int
+	Textures     int
+
+	// Skipped counts changes that were redundant.
+	Skipped int
+}
+
+// Changes returns the total number of state changes.
+func (ss *StateStats) Changes() int {
+	return ss.Programs + ss.VertexArrays + ss.Buffers + ss.Blends +
+		ss.PolygonModes + ss.Viewports + ss.Scissors + ss.Textures
+}
+
+// stateDriver makes the actual GL calls.
+type stateDriver interface {
+	useProgram(program uint32)
+	bindVertexArray(vao uint32)
+	bindBuffer(target, buffer uint32)
+	enable(capability uint32, enabled bool)
+	blendFunc(src, dst uint32)
+	polygonMode(mode uint32)
+	viewport(x, y, width, height int32)
+	scissor(x, y, width, height int32)
+	activeTexture(unit uint32)
+	bindTexture(texture uint32)
+}
+
+// StateCache owns the current GL program, VAO, buffers, blending, polygon
+// mode, viewport, scissor and 2D textures. Changes matching the current
+// state are skipped. All GL state changes must go through it, or
+// Invalidate must be called after changing state directly.
+type StateCache struct {
+	driver stateDriver
+
+	program       uint32
+	vertexArray   uint32
+	arrayBuffer   uint32
+	elementBuffer uint32
+
+	blendEnabled uint32
+	blendSrc     uint32
+	blendDst     uint32
+
+	polygonMode uint32
+
+	viewport      [4]int32
+	viewportKnown bool
+
+	scissorEnabled uint32
+	scissor        [4]int32
+	scissorKnown   bool
+
+	activeUnit uint32
+	textures   [maxTextureUnits]uint32
+
+	// Stats of the current frame.
+	Stats StateStats
+	// LastFrame are the stats of the previous frame, see EndFrame.
+	LastFrame StateStats
+}
+
+// GLState is the state cache of the engine's GL context.
+var GLState = NewStateCache()
+
+// NewStateCache creates a cache where all state is unknown.
+func NewStateCache() *StateCache {
+	return newStateCache(glDriver{})
+}
+
+func newStateCache(driver stateDriver) *StateCache {
+	sc := new(StateCache)
+	sc.driver = driver
+	sc.Invalidate()
+	return sc
+}
+
+// Invalidate forgets all state so the next changes are always made, for
+// example, after GL calls that bypass the cache.
+func (sc *StateCache) Invalidate() {
+	sc.program = unknown
+	sc.vertexArray = unknown
+	sc.arrayBuffer = unknown
+	sc.elementBuffer = unknown
+	sc.blendEnabled = unknown
+	sc.blendSrc = unknown
+	sc.blendDst = unknown
+	sc.polygonMode = unknown
+	sc.viewportKnown = false
+	sc.scissorEnabled = unknown
+	sc.scissorKnown = false
+	sc.activeUnit = unknown
+	for i := range sc.textures {
+		sc.textures[i] = unknown
+	}
+}
+
+// EndFrame moves the frame's Stats to LastFrame and resets them.
+func (sc *StateCache) EndFrame() {
+	sc.LastFrame = sc.Stats
+	sc.Stats = StateStats{}
+}
+
+// UseProgram makes a shader program current.
+func (sc *StateCache) UseProgram(program uint32) {
+	if sc.program == program {
+		sc.Stats.Skipped++
+		return
+	}
+	sc.program = program
+	sc.driver.useProgram(program)
+	sc.Stats.Programs++
+}
+
+// BindVertexArray binds a VAO, 0 unbinds.
+func (sc *StateCache) BindVertexArray(vao uint32) {
+	if sc.vertexArray == vao {
+		sc.Stats.Skipped++
+		return
+	}
+	sc.vertexArray = vao
+	sc.driver.bindVertexArray(vao)
+	sc.Stats.VertexArrays++
+
+	// The element buffer binding belongs to the VAO.
+	sc.elementBuffer = unknown
+}
+
+// BindBuffer binds a buffer to a target, for example, gl.ARRAY_BUFFER.
+func (sc *StateCache) BindBuffer(target, buffer uint32) {
+	var current *uint32
+	switch target {
+	case gl.ARRAY_BUFFER:
+		current = &sc.arrayBuffer
+	case gl.ELEMENT_ARRAY_BUFFER:
+		current = &sc.elementBuffer
+	}
+
+	if current != nil {
+		if *current == buffer {
+			sc.Stats.Skipped++
+			return
+		}
+		*current = buffer
+	}
+
+	sc.driver.bindBuffer(target, buffer)
+	sc.Stats.Buffers++
+}
+
+// EnableBlend enables or disables blending.
+func (sc *StateCache) EnableBlend(enabled bool) {
+	state := boolState(enabled)
+	if sc.blendEnabled == state {
+		sc.Stats.Skipped++
+		return
+	}
+	sc.blendEnabled = state
+	sc.driver.enable(gl.BLEND, enabled)
+	sc.Stats.Blends++
+}
+
+// BlendFunc sets the blend factors, for example, gl.SRC_ALPHA and
+// gl.ONE_MINUS_SRC_ALPHA.
+func (sc *StateCache) BlendFunc(src, dst uint32) {
+	if sc.blendSrc == src && sc.blendDst == dst {
+		sc.Stats.Skipped++
+		return
+	}
+	sc.blendSrc, sc.blendDst = src, dst
+	sc.driver.blendFunc(src, dst)
+	sc.Stats.Blends++
+}
+
+// PolygonMode sets how polygons are rasterised, for example, gl.FILL or
+// gl.LINE.
+func (sc *StateCache) PolygonMode(mode uint32) {
+	if sc.polygonMode == mode {
+		sc.Stats.Skipped++
+		return
+	}
+	sc.polygonMode = mode
+	sc.driver.polygonMode(mode)
+	sc.Stats.PolygonModes++
+}
+
+// Viewport sets the viewport.
+func (sc *StateCache) Viewport(x, y, width, height int32) {
+	rect := [4]int32{x, y, width, height}
+	if sc.viewportKnown && sc.viewport == rect {
+		sc.Stats.Skipped++
+		return
+	}
+	sc.viewport = rect
+	sc.viewportKnown = true
+	sc.driver.viewport(x, y, width, height)
+	sc.Stats.Viewports++
+}
+
+// EnableScissor enables or disables the scissor test.
+func (sc *StateCache) EnableScissor(enabled bool) {
+	state := boolState(enabled)
+	if sc.scissorEnabled == state {
+		sc.Stats.Skipped++
+		return
+	}
+	sc.scissorEnabled = state
+	sc.driver.enable(gl.SCISSOR_TEST, enabled)
+	sc.Stats.Scissors++
+}
+
+// Scissor sets the scissor rectangle.
+func (sc *StateCache) Scissor(x, y, width, height int32) {
+	rect := [4]int32{x, y, width, height}
+	if sc.scissorKnown && sc.scissor == rect {
+		sc.Stats.Skipped++
+		return
+	}
+	sc.scissor = rect
+	sc.scissorKnown = true
+	sc.driver.scissor(x, y, width, height)
+	sc.Stats.Scissors++
+}
+
+// BindTexture binds a 2D texture to a texture unit, 0 unbinds. The unit is
+// left active.
+func (sc *StateCache) BindTexture(unit, texture uint32) {
+	if unit >= maxTextureUnits {
+		sc.driver.activeTexture(unit)
+		sc.driver.bindTexture(texture)
+		sc.activeUnit = unit
+		sc.Stats.Textures++
+		return
+	}
+
+	// The unit is made active even if the texture is already bound, as
+	// uploads and parameter changes act on the active unit.
+	if sc.activeUnit != unit {
+		sc.activeUnit = unit
+		sc.driver.activeTexture(unit)
+	}
+
+	if sc.textures[unit] == texture {
+		sc.Stats.Skipped++
+		return
+	}
+
+	sc.textures[unit] = texture
+	sc.driver.bindTexture(texture)
+	sc.Stats.Textures++
+}
+
+// ProgramDeleted must be called when a program is deleted. GL keeps using
+// a deleted current program until another is made current.
+func (sc *StateCache) ProgramDeleted(program uint32) {
+	if sc.program == program {
+		sc.program = unknown
+	}
+}
+
+// VertexArrayDeleted must be called when a VAO is deleted, GL unbinds it.
+func (sc *StateCache) VertexArrayDeleted(vao uint32) {
+	if sc.vertexArray == vao {
+		sc.vertexArray = 0
+		sc.elementBuffer = unknown
+	}
+}
+
+// BufferDeleted must be called when a buffer is deleted, GL unbinds it.
+func (sc *StateCache) BufferDeleted(buffer uint32) {
+	if sc.arrayBuffer == buffer {
+		sc.arrayBuffer = 0
+	}
+	if sc.elementBuffer == buffer {
+		sc.elementBuffer = 0
+	}
+}
+
+// TextureDeleted must be called when a texture is deleted, GL unbinds it.
+func (sc *StateCache) TextureDeleted(texture uint32) {
+	for i := range sc.textures {
+		if sc.textures[i] == texture {
+			sc.textures[i] = 0
+		}
+	}
+}
+
+func boolState(enabled bool) uint32 {
+	if enabled {
+		return 1
+	}
+	return 0
+}
+
+// glDriver calls GL.
+type glDriver struct{}
+
+func (glDriver) useProgram(program uint32) {
+	gl.UseProgram(program)
+}
+
+func (glDriver) bindVertexArray(vao uint32) {
+	gl.BindVertexArray(vao)
+}
+
+func (glDriver) bindBuffer(target, buffer uint32) {
+	gl.BindBuffer(target, buffer)
+}
+
+func (glDriver) enable(capability uint32, enabled bool) {
+	if enabled {
+		gl.Enable(capability)
+	} else {
+		gl.Disable(capability)
+	}
+}
+
+func (glDriver) blendFunc(src, dst uint32) {
+	gl.BlendFunc(src, dst)
+}
+
+func (glDriver) polygonMode(mode uint32) {
+	gl.PolygonMode(gl.FRONT_AND_BACK, mode)
+}
+
+func (glDriver) viewport(x, y, width, height int32) {
+	gl.Viewport(x, y, width, height)
+}
+
+func (glDriver) scissor(x, y, width, height int32) {
+	gl.Scissor(x, y, width, height)
+}
+
+func (glDriver) activeTexture(unit uint32) {
+	gl.ActiveTexture(gl.TEXTURE0 + unit)
+}
+
+func (glDriver) bindTexture(texture uint32) {
+	gl.BindTexture(gl.TEXTURE_2D, texture)
+}
@@ -0,0 +1,109 @@
+package graphics
+
+import (
+	"testing"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+)
+
+// countingDriver counts the GL calls a StateCache makes.
+type countingDriver struct {
+	calls int
+	// The last unit made active
+	activeUnit uint32
+}
+
+func (cd *countingDriver) useProgram(program uint32)              { cd.calls++ }
+func (cd *countingDriver) bindVertexArray(vao uint32)             { cd.calls++ }
+func (cd *countingDriver) bindBuffer(target, buffer uint32)       { cd.calls++ }
+func (cd *countingDriver) enable(capability uint32, enabled bool) { cd.calls++ }
+func (cd *countingDriver) blendFunc(src, dst uint32)              { cd.calls++ }
+func (cd *countingDriver) polygonMode(mode uint32)                { cd.calls++ }
+func (cd *countingDriver) viewport(x, y, width, height int32)     { cd.calls++ }
+func (cd *countingDriver) scissor(x, y, width, height int32)      { cd.calls++ }
+func (cd *countingDriver) activeTexture(unit uint32)              { cd.calls++; cd.activeUnit = unit }
+func (cd *countingDriver) bindTexture(texture uint32)             { cd.calls++ }
+
+func Test_StateCache_SkipsRedundant(t *testing.T) {
+	driver := &countingDriver{}
+	sc := newStateCache(driver)
+
+	for i := 0; i < 10; i++ {
+		sc.UseProgram(3)
+		sc.BindVertexArray(4)
+		sc.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+		sc.EnableBlend(true)
+		sc.Viewport(0, 0, 800, 600)
+		sc.BindTexture(0, 7)
+	}
+
+	if driver.calls != 7 {
+		t.Errorf("Expected 7 GL calls (a texture unit change too), got: %d", driver.calls)
+	}
+	if sc.Stats.Changes() != 6 {
+		t.Errorf("Expected 6 state changes, got: %d", sc.Stats.Changes())
+	}
+	if sc.Stats.Skipped != 54 {
+		t.Errorf("Expected 54 skipped changes, got: %d", sc.Stats.Skipped)
+	}
+
+	sc.EndFrame()
+	if sc.LastFrame.Programs != 1 || sc.Stats.Changes() != 0 {
+		t.Errorf("Expected the frame's stats to move to LastFrame")
+	}
+
+	sc.Invalidate()
+	sc.UseProgram(3)
+	if sc.Stats.Programs != 1 {
+		t.Errorf("Expected a program change after Invalidate")
+	}
+}
+
+func Test_StateCache_VertexArrayOwnsElementBuffer(t *testing.T) {
+	driver := &countingDriver{}
+	sc := newStateCache(driver)
+
+	sc.BindVertexArray(1)
+	sc.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, 5)
+	sc.BindBuffer(gl.ARRAY_BUFFER, 6)
+
+	sc.BindVertexArray(2)
+	sc.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, 5)
+	sc.BindBuffer(gl.ARRAY_BUFFER, 6)
+
+	if sc.Stats.Buffers != 3 {
+		t.Errorf("Expected the element buffer to be rebound for the new VAO, got: %d buffer changes", sc.Stats.Buffers)
+	}
+
+	sc.BufferDeleted(6)
+	sc.BindBuffer(gl.ARRAY_BUFFER, 6)
+	if sc.Stats.Buffers != 4 {
+		t.Errorf("Expected a deleted buffer to be rebound, got: %d buffer changes", sc.Stats.Buffers)
+	}
+
+	sc.ProgramDeleted(0)
+	sc.UseProgram(9)
+	sc.ProgramDeleted(9)
+	sc.UseProgram(9)
+	if sc.Stats.Programs != 2 {
+		t.Errorf("Expected a deleted program to be made current again, got: %d", sc.Stats.Programs)
+	}
+}
+
+func Test_StateCache_BindTextureActivatesUnit(t *testing.T) {
+	driver := &countingDriver{}
+	sc := newStateCache(driver)
+
+	sc.BindTexture(0, 7)
+	sc.BindTexture(1, 8)
+
+	// Already bound, but uploads to unit 0 need it active.
+	sc.BindTexture(0, 7)
+
+	if driver.activeUnit != 0 {
+		t.Errorf("Expected unit 0 to be active, got: %d", driver.activeUnit)
+	}
+	if sc.Stats.Textures != 2 || sc.Stats.Skipped != 1 {
+		t.Errorf("Expected 2 texture binds and 1 skipped, got: %d %d", sc.Stats.Textures, sc.Stats.Skipped)
+	}
+}
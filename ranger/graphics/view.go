@@ -29,3 +29,13 @@ func (v *View) SetProjection(xOffset, yOffset, zOffset float32) {
 	// For this engine x and y are always zero and z is a small negative number like -1.0
 	v.Matrix.SetTranslate3Comp(v.xOffset, v.yOffset, v.zOffset)
 }
+
+// ApplyCamera sets the view matrix to the view offsets combined with a
+// camera transform, for example, from a CameraController.
+// i.e. [offsets] x [camera]
+func (v *View) ApplyCamera(camera *rmath.Matrix4) {
+	var offsets rmath.Matrix4
+	offsets.SetTranslate3Comp(v.xOffset, v.yOffset, v.zOffset)
+
+	rmath.Multiply(&offsets, camera, &v.Matrix)
+}
@@ -1,7 +1,9 @@
 // Package graphics provides visual
 package graphics
 
-import "github.com/go-gl/gl/v4.5-core/gl"
+import (
+	"github.com/wdevore/ranger/rmath"
+)
 
 // Viewport is a basic wrapper of an OpenGL viewport
 type Viewport struct {
@@ -24,5 +26,68 @@ func (v *Viewport) SetDimensions(x, y, width, height int) {
 
 // Apply set the actual OpenGL viewport
 func (v *Viewport) Apply() {
-	gl.Viewport(v.x, v.y, v.width, v.height)
+	GLState.Viewport(v.x, v.y, v.width, v.height)
+}
+
+// ApplyScissor restricts rendering, including clears, to the viewport.
+func (v *Viewport) ApplyScissor() {
+	GLState.EnableScissor(true)
+	GLState.Scissor(v.x, v.y, v.width, v.height)
+}
+
+// DisableScissor removes any scissor restriction.
+func (v *Viewport) DisableScissor() {
+	GLState.EnableScissor(false)
+}
+
+// X returns the lower left x coordinate
+func (v *Viewport) X() int {
+	return int(v.x)
+}
+
+// Y returns the lower left y coordinate
+func (v *Viewport) Y() int {
+	return int(v.y)
+}
+
+// Width returns the viewport's width
+func (v *Viewport) Width() int {
+	return int(v.width)
+}
+
+// Height returns the viewport's height
+func (v *Viewport) Height() int {
+	return int(v.height)
+}
+
+// DeviceToNDC maps device coordinates (window pixels with an upper left origin,
+// as reported by the window system) into normalized device coordinates
+// [-1.0, 1.0] relative to this viewport. windowHeight is the device height.
+func (v *Viewport) DeviceToNDC(x, y float32, windowHeight int, out *rmath.Vector3) {
+	// Flip Y because OpenGL's origin is the lower left.
+	glY := float32(windowHeight) - y
+
+	out.Set3Components(
+		2.0*(x-float32(v.x))/float32(v.width)-1.0,
+		2.0*(glY-float32(v.y))/float32(v.height)-1.0,
+		0.0)
+}
+
+// NDCToDevice maps normalized device coordinates into device coordinates
+// (window pixels with an upper left origin). windowHeight is the device height.
+func (v *Viewport) NDCToDevice(ndc *rmath.Vector3, windowHeight int, out *rmath.Vector3) {
+	glY := (ndc.Y+1.0)/2.0*float32(v.height) + float32(v.y)
+
+	out.Set3Components(
+		(ndc.X+1.0)/2.0*float32(v.width)+float32(v.x),
+		float32(windowHeight)-glY,
+		0.0)
+}
+
+// ContainsDevice checks if device coordinates (upper left origin) are
+// within this viewport.
+func (v *Viewport) ContainsDevice(x, y float32, windowHeight int) bool {
+	glY := float32(windowHeight) - y
+	return x >= float32(v.x) && x < float32(v.x+v.width) &&
+		glY >= float32(v.y) && glY < float32(v.y+v.height)
 }
@@ -0,0 +1,89 @@
+package graphics
+
+import (
+	"testing"
+
+	"github.com/wdevore/ranger/rmath"
+)
+
+func Test_Viewport_DeviceToNDC(t *testing.T) {
+	vp := NewViewport()
+	vp.SetDimensions(0, 0, 1500, 900)
+
+	ndc := rmath.NewVector3()
+
+	// Upper left corner of the window
+	vp.DeviceToNDC(0.0, 0.0, 900, ndc)
+	if !rmath.IsEqual(ndc.X, -1.0) || !rmath.IsEqual(ndc.Y, 1.0) {
+		t.Errorf("Expected <-1, 1>, got: %s", ndc)
+	}
+
+	// Center of the window
+	vp.DeviceToNDC(750.0, 450.0, 900, ndc)
+	if !rmath.IsEqual(ndc.X, 0.0) || !rmath.IsEqual(ndc.Y, 0.0) {
+		t.Errorf("Expected <0, 0>, got: %s", ndc)
+	}
+
+	device := rmath.NewVector3()
+	vp.NDCToDevice(ndc, 900, device)
+	if !rmath.IsEqual(device.X, 750.0) || !rmath.IsEqual(device.Y, 450.0) {
+		t.Errorf("Expected <750, 450>, got: %s", device)
+	}
+}
+
+func Test_Viewport_SplitDeviceToNDC(t *testing.T) {
+	// Right half of a split screen
+	vp := NewViewport()
+	vp.SetDimensions(750, 0, 750, 900)
+
+	ndc := rmath.NewVector3()
+	vp.DeviceToNDC(1125.0, 450.0, 900, ndc)
+	if !rmath.IsEqual(ndc.X, 0.0) || !rmath.IsEqual(ndc.Y, 0.0) {
+		t.Errorf("Expected <0, 0>, got: %s", ndc)
+	}
+
+	if vp.ContainsDevice(100.0, 450.0, 900) {
+		t.Error("Expected left half to be outside viewport")
+	}
+
+	if !vp.ContainsDevice(1000.0, 10.0, 900) {
+		t.Error("Expected point to be inside viewport")
+	}
+}
+
+func Test_Camera_CenteredUnproject(t *testing.T) {
+	// Device 1500x900 with a virtual 1000x600 has a ratio correction of 1.5
+	c := NewCamera()
+	c.SetProjection(1.5, 0.0, 0.0, 900.0, 1500.0)
+	c.Centered()
+
+	var inv rmath.Matrix4
+	inv.Invert(&c.Matrix)
+
+	// Right edge of NDC maps to half the virtual width.
+	v := rmath.NewVector3With2Components(1.0, 1.0)
+	v.Mul(&inv)
+	if !rmath.IsEqual(v.X, 500.0) || !rmath.IsEqual(v.Y, 300.0) {
+		t.Errorf("Expected <500, 300>, got: %s", v)
+	}
+}
+
+func Test_Camera_UncenteredUnproject(t *testing.T) {
+	c := NewCamera()
+	c.SetProjection(1.5, 0.0, 0.0, 900.0, 1500.0)
+
+	var inv rmath.Matrix4
+	inv.Invert(&c.Matrix)
+
+	v := rmath.NewVector3With2Components(-1.0, -1.0)
+	v.Mul(&inv)
+	if !rmath.IsEqual(v.X, 0.0) || !rmath.IsEqual(v.Y, 0.0) {
+		t.Errorf("Expected <0, 0>, got: %s", v)
+	}
+
+	v.Set2Components(1.0, 1.0)
+	v.Mul(&inv)
+	if !rmath.IsEqual(v.X, 1000.0) || !rmath.IsEqual(v.Y, 600.0) {
+		t.Errorf("Expected <1000, 600>, got: %s", v)
+	}
+}
@@ -0,0 +1,101 @@
+package ranger
+
+import (
+	"github.com/wdevore/ranger/graphics"
+	"github.com/wdevore/ranger/rmath"
+)
+
+// NodeVisitor is the root of what a RenderView renders, typically a
+// scene graph Node.
+type NodeVisitor interface {
+	Visit(dt float32, modelT *rmath.Matrix4) bool
+}
+
+// Flusher draws what was collected while visiting, for example, a
+// rendering.VectorBatchRenderer.
+type Flusher interface {
+	Flush()
+}
+
+// RenderView is a region of the window rendered through its own Viewport,
+// Camera and View. Several RenderViews can share the same Root (a shared
+// world) or each have their own.
+type RenderView struct {
+	Name string
+
+	Viewport *graphics.Viewport
+	Camera   *graphics.Camera
+	View     *graphics.View
+
+	// Controller is optional. If present it updates the View each frame.
+	Controller *graphics.CameraController
+
+	// Root is visited each frame with the view-projection matrix.
+	Root NodeVisitor
+
+	// Flushers are flushed, in order, after the Root is visited.
+	Flushers []Flusher
+
+	// ClearColor is optional. If present the view's region is cleared
+	// before rendering.
+	ClearColor *graphics.Colors
+
+	Visible bool
+
+	// Height of the device (aka window) used for mapping device coordinates.
+	windowHeight int
+
+	viewProjection rmath.Matrix4
+}
+
+// NewRenderView creates a visible RenderView with its own Viewport,
+// Camera and View.
+func NewRenderView(name string) *RenderView {
+	rv := new(RenderView)
+	rv.Name = name
+	rv.Viewport = graphics.NewViewport()
+	rv.Camera = graphics.NewCamera()
+	rv.View = graphics.NewView()
+	rv.Visible = true
+	return rv
+}
+
+// ViewProjection returns the combined projection and view matrix computed
+// during the last Render.
+func (rv *RenderView) ViewProjection() *rmath.Matrix4 {
+	return &rv.viewProjection
+}
+
+// Update advances the Controller (if any) and recomputes the
+// view-projection matrix.
+func (rv *RenderView) Update(dt float32) {
+	if rv.Controller != nil {
+		rv.Controller.Update(dt)
+		rv.Controller.Apply(rv.View)
+	}
+
+	// [projection] x [view]
+	rmath.Multiply(&rv.Camera.Matrix, &rv.View.Matrix, &rv.viewProjection)
+}
+
+// Render restricts rendering to the Viewport and visits the Root.
+func (rv *RenderView) Render(dt float32, context *graphics.RenderContext) {
+	if !rv.Visible {
+		return
+	}
+
+	rv.Viewport.Apply()
+	rv.Viewport.ApplyScissor()
+
+	if rv.ClearColor != nil {
+		context.ClearRegion(rv.ClearColor)
+	}
+
+	if rv.Root != nil {
+		rv.Root.Visit(dt, &rv.viewProjection)
+	}
+
+	for _, f := range rv.Flushers {
+		f.Flush()
+	}
+}
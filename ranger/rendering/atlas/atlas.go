@@ -1,7 +1,10 @@
 // Package atlas defines vector shape collections
 package atlas
 
-import "github.com/wdevore/ranger/rendering"
+import (
+	"github.com/wdevore/ranger/fonts"
+	"github.com/wdevore/ranger/rendering"
+)
 
 // Atlas is a map-collection of vector shapes managed by a vector object
 type Atlas struct {
@@ -11,9 +14,28 @@ type Atlas struct {
 
 func (a *Atlas) initialize() {
 	a.Shapes = make(map[string]*rendering.VectorShape)
+	a.vo.Construct()
+}
+
+// Object returns the vector object holding the shapes' vertices
+func (a *Atlas) Object() *rendering.VectorObject {
+	return &a.vo
+}
+
+// Delete deletes the vector object's GL buffers.
+func (a *Atlas) Delete() {
+	a.vo.Delete()
 }
 
 // AddShape adds a vector shape to the collection
 func (a *Atlas) AddShape(vs *rendering.VectorShape) {
 	a.Shapes[vs.Name] = vs
 }
+
+// ShapeFont is an Atlas of glyph shapes that can lay out text, for example,
+// a VectorFontAtlas or StrokeFontAtlas.
+type ShapeFont interface {
+	Object() *rendering.VectorObject
+	Glyph(r rune) *rendering.VectorShape
+	Layout(text string, options fonts.LayoutOptions) *fonts.TextLayout
+}
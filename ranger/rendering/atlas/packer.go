@@ -0,0 +1,325 @@
+package atlas
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"sort"
+)
+
+// PackOptions controls how images are packed into pages.
+type PackOptions struct {
+	// Maximum page dimensions
+	MaxWidth, MaxHeight int
+	// Padding is the number of empty pixels around each image.
+	Padding int
+	// Trim removes fully transparent borders from each image.
+	Trim bool
+	// PowerOfTwo rounds each page's dimensions up to a power of two. Pages
+	// are then at most the largest powers of two within the maximums.
+	PowerOfTwo bool
+}
+
+// DefaultPackOptions returns 2048x2048 pages, 2 pixels of padding and trimming.
+func DefaultPackOptions() PackOptions {
+	return PackOptions{
+		MaxWidth:   2048,
+		MaxHeight:  2048,
+		Padding:    2,
+		Trim:       true,
+		PowerOfTwo: true,
+	}
+}
+
+// NamedImage is an image to be packed.
+type NamedImage struct {
+	Name  string
+	Image image.Image
+}
+
+// PackedPage is a packed page image with the frames it contains.
+type PackedPage struct {
+	Image  *image.NRGBA
+	Frames []FrameDescriptor
+}
+
+// Pack packs the images into one or more pages using a MaxRects
+// (best short side fit) bin packer.
+func Pack(images []NamedImage, options PackOptions) ([]*PackedPage, error) {
+	if options.MaxWidth <= 0 || options.MaxHeight <= 0 {
+		return nil, errors.New("pack options require a positive MaxWidth and MaxHeight")
+	}
+
+	// Pages are packed within the bin so rounding them up can't exceed
+	// the maximums.
+	binWidth, binHeight := options.MaxWidth, options.MaxHeight
+	if options.PowerOfTwo {
+		binWidth = prevPowerOfTwo(binWidth)
+		binHeight = prevPowerOfTwo(binHeight)
+	}
+
+	items := make([]*packItem, 0, len(images))
+
+	for _, ni := range images {
+		item := newPackItem(ni, options.Trim)
+		w := item.trimmed.Dx() + options.Padding*2
+		h := item.trimmed.Dy() + options.Padding*2
+
+		if w > binWidth || h > binHeight {
+			return nil, fmt.Errorf("image '%s' (%d x %d) doesn't fit a %d x %d page",
+				ni.Name, w, h, binWidth, binHeight)
+		}
+
+		items = append(items, item)
+	}
+
+	// Larger items first packs more tightly.
+	sort.SliceStable(items, func(i, j int) bool {
+		ai := items[i].trimmed.Dx() * items[i].trimmed.Dy()
+		aj := items[j].trimmed.Dx() * items[j].trimmed.Dy()
+		return ai > aj
+	})
+
+	pages := []*PackedPage{}
+
+	for len(items) > 0 {
+		bin := newMaxRectsBin(binWidth, binHeight)
+		remaining := []*packItem{}
+		placed := []*packItem{}
+
+		for _, item := range items {
+			w := item.trimmed.Dx() + options.Padding*2
+			h := item.trimmed.Dy() + options.Padding*2
+
+			r, ok := bin.insert(w, h)
+			if !ok {
+				remaining = append(remaining, item)
+				continue
+			}
+
+			item.position = image.Pt(r.Min.X+options.Padding, r.Min.Y+options.Padding)
+			placed = append(placed, item)
+		}
+
+		pages = append(pages, buildPage(placed, len(pages), options))
+		items = remaining
+	}
+
+	return pages, nil
+}
+
+// ---------------------------------------------------------------------
+// Items
+// ---------------------------------------------------------------------
+
+type packItem struct {
+	name  string
+	image image.Image
+	// trimmed is the region of the image that is kept.
+	trimmed image.Rectangle
+	// position is where the trimmed region is placed on the page.
+	position image.Point
+}
+
+func newPackItem(ni NamedImage, trim bool) *packItem {
+	item := &packItem{name: ni.Name, image: ni.Image}
+
+	item.trimmed = ni.Image.Bounds()
+
+	if trim {
+		item.trimmed = opaqueBounds(ni.Image)
+	}
+
+	return item
+}
+
+// opaqueBounds returns the smallest rectangle containing all pixels with
+// a non-zero alpha. A fully transparent image yields a 1x1 rectangle.
+func opaqueBounds(img image.Image) image.Rectangle {
+	b := img.Bounds()
+	minX, minY, maxX, maxY := b.Max.X, b.Max.Y, b.Min.X-1, b.Min.Y-1
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			_, _, _, a := img.At(x, y).RGBA()
+			if a == 0 {
+				continue
+			}
+			if x < minX {
+				minX = x
+			}
+			if x > maxX {
+				maxX = x
+			}
+			if y < minY {
+				minY = y
+			}
+			if y > maxY {
+				maxY = y
+			}
+		}
+	}
+
+	if maxX < minX {
+		return image.Rect(b.Min.X, b.Min.Y, b.Min.X+1, b.Min.Y+1)
+	}
+
+	return image.Rect(minX, minY, maxX+1, maxY+1)
+}
+
+func buildPage(items []*packItem, pageIndex int, options PackOptions) *PackedPage {
+	width, height := 1, 1
+
+	for _, item := range items {
+		r := item.trimmed.Sub(item.trimmed.Min).Add(item.position)
+		if r.Max.X+options.Padding > width {
+			width = r.Max.X + options.Padding
+		}
+		if r.Max.Y+options.Padding > height {
+			height = r.Max.Y + options.Padding
+		}
+	}
+
+	if options.PowerOfTwo {
+		width = nextPowerOfTwo(width)
+		height = nextPowerOfTwo(height)
+	}
+
+	page := &PackedPage{Image: image.NewNRGBA(image.Rect(0, 0, width, height))}
+
+	for _, item := range items {
+		dst := image.Rectangle{Min: item.position, Max: item.position.Add(item.trimmed.Size())}
+		draw.Draw(page.Image, dst, item.image, item.trimmed.Min, draw.Src)
+
+		src := item.image.Bounds()
+
+		page.Frames = append(page.Frames, FrameDescriptor{
+			Name:         item.name,
+			Page:         pageIndex,
+			X:            item.position.X,
+			Y:            item.position.Y,
+			Width:        item.trimmed.Dx(),
+			Height:       item.trimmed.Dy(),
+			Trimmed:      item.trimmed != src,
+			SourceWidth:  src.Dx(),
+			SourceHeight: src.Dy(),
+			OffsetX:      item.trimmed.Min.X - src.Min.X,
+			OffsetY:      item.trimmed.Min.Y - src.Min.Y,
+		})
+	}
+
+	return page
+}
+
+func nextPowerOfTwo(v int) int {
+	p := 1
+	for p < v {
+		p <<= 1
+	}
+	return p
+}
+
+// prevPowerOfTwo returns the largest power of two <= v (v > 0).
+func prevPowerOfTwo(v int) int {
+	p := 1
+	for p<<1 <= v {
+		p <<= 1
+	}
+	return p
+}
+
+// ---------------------------------------------------------------------
+// MaxRects bin
+// ---------------------------------------------------------------------
+
+type maxRectsBin struct {
+	free []image.Rectangle
+}
+
+func newMaxRectsBin(width, height int) *maxRectsBin {
+	b := new(maxRectsBin)
+	b.free = []image.Rectangle{image.Rect(0, 0, width, height)}
+	return b
+}
+
+// insert places a width x height rectangle using best short side fit.
+func (b *maxRectsBin) insert(width, height int) (image.Rectangle, bool) {
+	bestShort, bestLong := int(^uint(0)>>1), int(^uint(0)>>1)
+	var best image.Rectangle
+	found := false
+
+	for _, f := range b.free {
+		if f.Dx() < width || f.Dy() < height {
+			continue
+		}
+
+		leftX := f.Dx() - width
+		leftY := f.Dy() - height
+		short, long := leftX, leftY
+		if short > long {
+			short, long = long, short
+		}
+
+		if short < bestShort || (short == bestShort && long < bestLong) {
+			bestShort, bestLong = short, long
+			best = image.Rect(f.Min.X, f.Min.Y, f.Min.X+width, f.Min.Y+height)
+			found = true
+		}
+	}
+
+	if !found {
+		return best, false
+	}
+
+	b.place(best)
+
+	return best, true
+}
+
+// place splits every free rectangle the used rectangle overlaps and then
+// removes free rectangles contained by others.
+func (b *maxRectsBin) place(used image.Rectangle) {
+	free := make([]image.Rectangle, 0, len(b.free)+4)
+
+	for _, f := range b.free {
+		if !f.Overlaps(used) {
+			free = append(free, f)
+			continue
+		}
+
+		if used.Min.X > f.Min.X {
+			free = append(free, image.Rect(f.Min.X, f.Min.Y, used.Min.X, f.Max.Y))
+		}
+		if used.Max.X < f.Max.X {
+			free = append(free, image.Rect(used.Max.X, f.Min.Y, f.Max.X, f.Max.Y))
+		}
+		if used.Min.Y > f.Min.Y {
+			free = append(free, image.Rect(f.Min.X, f.Min.Y, f.Max.X, used.Min.Y))
+		}
+		if used.Max.Y < f.Max.Y {
+			free = append(free, image.Rect(f.Min.X, used.Max.Y, f.Max.X, f.Max.Y))
+		}
+	}
+
+	// Prune
+	pruned := make([]image.Rectangle, 0, len(free))
+	for i, a := range free {
+		contained := false
+		for j, o := range free {
+			if i == j {
+				continue
+			}
+			// Keep the first of two identical rectangles.
+			if a.In(o) && (a != o || j < i) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			pruned = append(pruned, a)
+		}
+	}
+
+	b.free = pruned
+}
@@ -0,0 +1,140 @@
+package atlas
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func solidImage(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 255
+	}
+	return img
+}
+
+func Test_Pack_NoOverlaps(t *testing.T) {
+	images := []NamedImage{}
+	for i := 0; i < 40; i++ {
+		images = append(images, NamedImage{
+			Name:  fmt.Sprintf("img%d", i),
+			Image: solidImage(8+(i*7)%29, 6+(i*5)%31),
+		})
+	}
+
+	options := PackOptions{MaxWidth: 256, MaxHeight: 256, Padding: 1}
+	pages, err := Pack(images, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count := 0
+	for _, page := range pages {
+		rects := []image.Rectangle{}
+		for _, f := range page.Frames {
+			r := image.Rect(f.X-1, f.Y-1, f.X+f.Width+1, f.Y+f.Height+1)
+
+			if !r.In(image.Rect(-1, -1, 257, 257)) {
+				t.Errorf("Frame '%s' is outside the page: %v", f.Name, r)
+			}
+
+			for _, o := range rects {
+				if r.Inset(1).Overlaps(o.Inset(1)) {
+					t.Errorf("Frame '%s' overlaps another frame", f.Name)
+				}
+			}
+			rects = append(rects, r)
+			count++
+		}
+	}
+
+	if count != len(images) {
+		t.Errorf("Expected %d frames, got: %d", len(images), count)
+	}
+}
+
+func Test_Pack_MultiplePages(t *testing.T) {
+	images := []NamedImage{
+		{Name: "a", Image: solidImage(60, 60)},
+		{Name: "b", Image: solidImage(60, 60)},
+	}
+
+	pages, err := Pack(images, PackOptions{MaxWidth: 64, MaxHeight: 64})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pages) != 2 {
+		t.Fatalf("Expected 2 pages, got: %d", len(pages))
+	}
+
+	if pages[1].Frames[0].Page != 1 {
+		t.Errorf("Expected frame on page 1, got: %d", pages[1].Frames[0].Page)
+	}
+}
+
+func Test_Pack_TooLarge(t *testing.T) {
+	images := []NamedImage{{Name: "big", Image: solidImage(100, 10)}}
+
+	_, err := Pack(images, PackOptions{MaxWidth: 64, MaxHeight: 64})
+	if err == nil {
+		t.Error("Expected an error for an image larger than a page")
+	}
+}
+
+func Test_Pack_PowerOfTwoWithinMax(t *testing.T) {
+	images := []NamedImage{}
+	for i := 0; i < 6; i++ {
+		images = append(images, NamedImage{Name: fmt.Sprintf("img%d", i), Image: solidImage(520, 100)})
+	}
+
+	// Two side by side are 1040 wide, which rounds up to 2048.
+	options := PackOptions{MaxWidth: 1100, MaxHeight: 1100, PowerOfTwo: true}
+	pages, err := Pack(images, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, page := range pages {
+		b := page.Image.Bounds()
+		if b.Dx() > options.MaxWidth || b.Dy() > options.MaxHeight {
+			t.Errorf("Expected page within %d x %d, got: %d x %d", options.MaxWidth, options.MaxHeight, b.Dx(), b.Dy())
+		}
+	}
+
+	big := []NamedImage{{Name: "big", Image: solidImage(1050, 10)}}
+	if _, err := Pack(big, options); err == nil {
+		t.Error("Expected an error for an image wider than the largest power of two page")
+	}
+}
+
+func Test_Pack_Trim(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 32, 32))
+	for y := 10; y < 20; y++ {
+		for x := 4; x < 12; x++ {
+			img.Set(x, y, color.NRGBA{255, 0, 0, 255})
+		}
+	}
+
+	pages, err := Pack([]NamedImage{{Name: "t", Image: img}}, PackOptions{MaxWidth: 64, MaxHeight: 64, Trim: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := pages[0].Frames[0]
+
+	if !f.Trimmed || f.Width != 8 || f.Height != 10 {
+		t.Errorf("Expected a trimmed 8 x 10 frame, got: %d x %d (%t)", f.Width, f.Height, f.Trimmed)
+	}
+
+	if f.OffsetX != 4 || f.OffsetY != 10 || f.SourceWidth != 32 || f.SourceHeight != 32 {
+		t.Errorf("Unexpected trim offsets: %+v", f)
+	}
+
+	r, _, _, _ := pages[0].Image.At(f.X, f.Y).RGBA()
+	if r == 0 {
+		t.Error("Expected trimmed pixels to be copied to the page")
+	}
+}
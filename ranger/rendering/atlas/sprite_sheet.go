@@ -0,0 +1,144 @@
+package atlas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/wdevore/ranger/rendering"
+	"github.com/wdevore/ranger/rmath"
+)
+
+// SheetDescriptor is the JSON descriptor written by the atlaspacker command.
+type SheetDescriptor struct {
+	Pages  []PageDescriptor
+	Frames []FrameDescriptor
+}
+
+// PageDescriptor describes a page image relative to the descriptor file.
+type PageDescriptor struct {
+	Image         string
+	Width, Height int
+}
+
+// FrameDescriptor locates a named frame on a page. X,Y is the upper left
+// texel. If Trimmed then OffsetX,OffsetY is where the trimmed region was
+// within the original SourceWidth x SourceHeight image.
+type FrameDescriptor struct {
+	Name          string
+	Page          int
+	X, Y          int
+	Width, Height int
+
+	Trimmed                   bool
+	SourceWidth, SourceHeight int
+	OffsetX, OffsetY          int
+}
+
+// Frame is a named region of a SpriteSheet page.
+type Frame struct {
+	Name    string
+	Texture *rendering.Texture
+	// Source is the region of the Texture in texels.
+	Source rmath.Rectangle
+
+	// Trim offset of the region's center relative to the original image's
+	// center, in texels with +Y upwards.
+	TrimOffset rmath.Vector3
+}
+
+// SpriteSheet is a map-collection of raster frames packed into one or
+// more textures. Sprites sharing a page share a texture.
+type SpriteSheet struct {
+	Pages  []*rendering.Texture
+	Frames map[string]*Frame
+}
+
+// NewSpriteSheet creates an empty SpriteSheet
+func NewSpriteSheet() *SpriteSheet {
+	ss := new(SpriteSheet)
+	ss.Frames = make(map[string]*Frame)
+	return ss
+}
+
+// LoadSpriteSheet reads a JSON descriptor and loads its page images, which
+// are relative to the descriptor.
+func LoadSpriteSheet(descriptorPath string, options rendering.TextureOptions) (*SpriteSheet, error) {
+	bytes, err := ioutil.ReadFile(descriptorPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var desc SheetDescriptor
+	err = json.Unmarshal(bytes, &desc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sprite sheet '%s': %s", descriptorPath, err.Error())
+	}
+
+	ss := NewSpriteSheet()
+	dir := filepath.Dir(descriptorPath)
+
+	for _, page := range desc.Pages {
+		tex, err := rendering.LoadTexture(filepath.Join(dir, page.Image), options)
+		if err != nil {
+			ss.Delete()
+			return nil, err
+		}
+		ss.Pages = append(ss.Pages, tex)
+	}
+
+	err = ss.AddFrames(desc.Frames)
+	if err != nil {
+		ss.Delete()
+		return nil, err
+	}
+
+	return ss, nil
+}
+
+// AddFrames adds frames referencing already loaded Pages.
+func (ss *SpriteSheet) AddFrames(frames []FrameDescriptor) error {
+	for _, fd := range frames {
+		if fd.Page < 0 || fd.Page >= len(ss.Pages) {
+			return fmt.Errorf("frame '%s' references missing page %d", fd.Name, fd.Page)
+		}
+
+		f := new(Frame)
+		f.Name = fd.Name
+		f.Texture = ss.Pages[fd.Page]
+		f.Source.Set(float32(fd.X), float32(fd.Y), float32(fd.Width), float32(fd.Height), false)
+
+		if fd.Trimmed {
+			f.TrimOffset.Set2Components(
+				float32(fd.OffsetX)+float32(fd.Width)/2.0-float32(fd.SourceWidth)/2.0,
+				-(float32(fd.OffsetY) + float32(fd.Height)/2.0 - float32(fd.SourceHeight)/2.0))
+		}
+
+		ss.Frames[f.Name] = f
+	}
+
+	return nil
+}
+
+// Delete deletes the page textures. Frames keep referencing them.
+func (ss *SpriteSheet) Delete() {
+	for _, page := range ss.Pages {
+		page.Delete()
+	}
+}
+
+// Frame returns a frame by name or nil.
+func (ss *SpriteSheet) Frame(name string) *Frame {
+	return ss.Frames[name]
+}
+
+// IconAspect returns a frame's width / height so frames can be inline
+// icons of rich text.
+func (ss *SpriteSheet) IconAspect(name string) (float32, bool) {
+	f := ss.Frames[name]
+	if f == nil || f.Source.Height == 0.0 {
+		return 0.0, false
+	}
+	return f.Source.Width / f.Source.Height, true
+}
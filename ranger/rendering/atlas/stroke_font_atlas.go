@@ -0,0 +1,78 @@
+package atlas
+
+import (
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/ranger/fonts"
+	"github.com/wdevore/ranger/rendering"
+)
+
+// StrokeFontAtlas is an Atlas of a StrokeFont's glyphs as line shapes.
+// Shapes are named "Stroke_" followed by the rune.
+type StrokeFontAtlas struct {
+	Atlas
+
+	Font *fonts.StrokeFont
+
+	glyphs map[rune]*rendering.VectorShape
+}
+
+// NewStrokeFontAtlas creates an atlas populated with the font's glyphs.
+// Call Object().Bind() to upload it.
+func NewStrokeFontAtlas(font *fonts.StrokeFont) *StrokeFontAtlas {
+	sfa := new(StrokeFontAtlas)
+	sfa.initialize()
+	sfa.Font = font
+	sfa.glyphs = make(map[rune]*rendering.VectorShape)
+	sfa.populate()
+	return sfa
+}
+
+func (sfa *StrokeFontAtlas) populate() {
+	uAtlas := sfa.vo.UniAtlas
+
+	for r, g := range sfa.Font.Glyphs {
+		if len(g.Segments) == 0 {
+			continue
+		}
+
+		s := buildStrokeGlyph(uAtlas, g)
+		sfa.glyphs[r] = s
+		sfa.AddShape(s)
+	}
+}
+
+// Glyph returns the shape drawn for a rune or nil.
+func (sfa *StrokeFontAtlas) Glyph(r rune) *rendering.VectorShape {
+	g := sfa.Font.Glyph(r)
+	if g == nil {
+		return nil
+	}
+
+	return sfa.glyphs[g.Rune]
+}
+
+// Layout positions each glyph of the text in ems.
+func (sfa *StrokeFontAtlas) Layout(text string, options fonts.LayoutOptions) *fonts.TextLayout {
+	return sfa.Font.Layout(text, options)
+}
+
+func buildStrokeGlyph(uAtlas *rendering.VectorUniformAtlas, g *fonts.StrokeGlyph) *rendering.VectorShape {
+	s := rendering.NewVectorShape()
+	s.Name = "Stroke_" + string(g.Rune)
+	s.PrimitiveMode = gl.LINES
+
+	s.SetOffset(uAtlas.Begin())
+
+	// Segments are in ems
+	for i := 0; i < len(g.Segments); i += 4 {
+		v0 := uAtlas.AddVertex(g.Segments[i], g.Segments[i+1], 0.0)
+		v1 := uAtlas.AddVertex(g.Segments[i+2], g.Segments[i+3], 0.0)
+
+		uAtlas.AddIndex(v0)
+		uAtlas.AddIndex(v1)
+	}
+
+	s.Count = int32(uAtlas.End())
+
+	return s
+}
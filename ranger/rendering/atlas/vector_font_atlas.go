@@ -0,0 +1,82 @@
+package atlas
+
+import (
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/ranger/fonts"
+	"github.com/wdevore/ranger/rendering"
+)
+
+// VectorFontAtlas is an Atlas of a VectorFont's glyphs as triangle shapes.
+// Shapes are named "Glyph_" followed by the rune.
+type VectorFontAtlas struct {
+	Atlas
+
+	Font *fonts.VectorFont
+
+	glyphs map[rune]*rendering.VectorShape
+}
+
+// NewVectorFontAtlas creates an atlas populated with the font's glyphs.
+// Call Object().Bind() to upload it.
+func NewVectorFontAtlas(font *fonts.VectorFont) *VectorFontAtlas {
+	vfa := new(VectorFontAtlas)
+	vfa.initialize()
+	vfa.Font = font
+	vfa.glyphs = make(map[rune]*rendering.VectorShape)
+	vfa.populate()
+	return vfa
+}
+
+func (vfa *VectorFontAtlas) populate() {
+	uAtlas := vfa.vo.UniAtlas
+
+	for r, g := range vfa.Font.Glyphs {
+		if len(g.Indices) == 0 {
+			continue
+		}
+
+		s := buildGlyph(uAtlas, g)
+		vfa.glyphs[r] = s
+		vfa.AddShape(s)
+	}
+}
+
+// Glyph returns the shape drawn for a rune or nil, for example, a space.
+func (vfa *VectorFontAtlas) Glyph(r rune) *rendering.VectorShape {
+	g := vfa.Font.Glyph(r)
+	if g == nil {
+		return nil
+	}
+
+	return vfa.glyphs[g.Rune]
+}
+
+// Layout positions each glyph of the text in ems.
+func (vfa *VectorFontAtlas) Layout(text string, options fonts.LayoutOptions) *fonts.TextLayout {
+	return vfa.Font.Layout(text, options)
+}
+
+func buildGlyph(uAtlas *rendering.VectorUniformAtlas, g *fonts.VectorGlyph) *rendering.VectorShape {
+	s := rendering.NewVectorShape()
+	s.Name = "Glyph_" + string(g.Rune)
+	s.PrimitiveMode = gl.TRIANGLES
+
+	s.SetOffset(uAtlas.Begin())
+
+	// Vertices are in ems
+	base := -1
+	for i := 0; i < len(g.Vertices); i += 2 {
+		v := uAtlas.AddVertex(g.Vertices[i], g.Vertices[i+1], 0.0)
+		if base < 0 {
+			base = v
+		}
+	}
+
+	for _, index := range g.Indices {
+		uAtlas.AddIndex(base + int(index))
+	}
+
+	s.Count = int32(uAtlas.End())
+
+	return s
+}
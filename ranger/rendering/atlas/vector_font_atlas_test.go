@@ -0,0 +1,70 @@
+package atlas
+
+import (
+	"testing"
+
+	"github.com/wdevore/ranger/fonts"
+)
+
+func Test_VectorFontAtlas_Shapes(t *testing.T) {
+	ttf, err := fonts.Neuropol()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	font, err := fonts.NewVectorFont("neuropol", ttf, []rune("AB "))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vfa := NewVectorFontAtlas(font)
+
+	if len(vfa.Shapes) != 2 {
+		t.Errorf("Expected 2 shapes (no space), got: %d", len(vfa.Shapes))
+	}
+
+	a := vfa.Glyph('A')
+	if a == nil || a.Name != "Glyph_A" {
+		t.Fatalf("Expected shape Glyph_A")
+	}
+
+	if int(a.Count) != len(font.Glyph('A').Indices) {
+		t.Errorf("Expected %d indices, got: %d", len(font.Glyph('A').Indices), a.Count)
+	}
+
+	total := len(font.Glyph('A').Vertices)/2 + len(font.Glyph('B').Vertices)/2
+	if vfa.Object().UniAtlas.ComponentCount != total {
+		t.Errorf("Expected %d vertices, got: %d", total, vfa.Object().UniAtlas.ComponentCount)
+	}
+
+	// Unknown runes use the fallback which isn't in this atlas.
+	if vfa.Glyph('Z') != nil {
+		t.Errorf("Expected no shape for 'Z'")
+	}
+}
+
+func Test_StrokeFontAtlas_Shapes(t *testing.T) {
+	font := fonts.NewStrokeFont()
+	sfa := NewStrokeFontAtlas(font)
+
+	var _ ShapeFont = sfa
+
+	if sfa.Glyph(' ') != nil {
+		t.Errorf("Expected no shape for a space")
+	}
+
+	// Lowercase is drawn with uppercase strokes.
+	if sfa.Glyph('a') != sfa.Glyph('A') {
+		t.Errorf("Expected 'a' to use the 'A' shape")
+	}
+
+	h := sfa.Glyph('H')
+	if h == nil {
+		t.Fatalf("Expected shape Stroke_H")
+	}
+
+	// H is 3 lines of 2 indices each.
+	if h.Count != 6 {
+		t.Errorf("Expected 6 indices, got: %d", h.Count)
+	}
+}
@@ -0,0 +1,37 @@
+// Package rendering defines blend modes.
+package rendering
+
+import (
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/ranger/graphics"
+)
+
+// BlendMode selects how fragments are combined with the framebuffer.
+type BlendMode int
+
+const (
+	// BlendAlpha is the Stage's default: src*a + dst*(1-a).
+	BlendAlpha BlendMode = iota
+	// BlendAdditive adds, for example, glows and particles: src*a + dst.
+	BlendAdditive
+	// BlendPremultiplied is for colours already multiplied by alpha.
+	BlendPremultiplied
+	// BlendNone overwrites the framebuffer.
+	BlendNone
+)
+
+// Apply configures GL's blending through the state cache.
+func (bm BlendMode) Apply() {
+	switch bm {
+	case BlendNone:
+		graphics.GLState.EnableBlend(false)
+		return
+	case BlendAdditive:
+		graphics.GLState.BlendFunc(gl.SRC_ALPHA, gl.ONE)
+	case BlendPremultiplied:
+		graphics.GLState.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
+	default:
+		graphics.GLState.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	}
+	graphics.GLState.EnableBlend(true)
+}
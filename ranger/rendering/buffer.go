@@ -0,0 +1,77 @@
+// Package rendering defines buffer usage and uploads shared by VBO and EBO.
+package rendering
+
+import (
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+)
+
+// BufferUsage hints how often a buffer's data changes.
+type BufferUsage uint32
+
+const (
+	// StaticUsage is uploaded once, for example, a font's glyph shapes.
+	// This is the default.
+	StaticUsage BufferUsage = gl.STATIC_DRAW
+	// DynamicUsage changes occasionally, for example, an atlas that is
+	// appended to. Its buffer grows in steps and is updated in place.
+	DynamicUsage BufferUsage = gl.DYNAMIC_DRAW
+	// StreamUsage is rewritten every frame, for example, particles. Its
+	// buffer is orphaned before each full upload so the GPU doesn't stall.
+	StreamUsage BufferUsage = gl.STREAM_DRAW
+)
+
+func (bu BufferUsage) glUsage() uint32 {
+	if bu == 0 {
+		return gl.STATIC_DRAW
+	}
+	return uint32(bu)
+}
+
+// bufferCapacity returns the bytes to allocate for 'size' bytes. Dynamic
+// and stream buffers grow geometrically so appending is cheap.
+func bufferCapacity(capacity, size int, usage BufferUsage) int {
+	if usage.glUsage() == gl.STATIC_DRAW {
+		return size
+	}
+
+	if capacity < 64 {
+		capacity = 64
+	}
+	for capacity < size {
+		capacity *= 2
+	}
+	return capacity
+}
+
+// uploadBuffer replaces the contents of the buffer bound to 'target' and
+// returns its capacity, which changes if it had to grow.
+func uploadBuffer(target uint32, capacity, size int, data unsafe.Pointer, usage BufferUsage) int {
+	switch {
+	case size > capacity || (usage.glUsage() == gl.STATIC_DRAW && size != capacity):
+		// (Re)allocate
+		capacity = bufferCapacity(capacity, size, usage)
+		if capacity == size {
+			gl.BufferData(target, size, data, usage.glUsage())
+			return capacity
+		}
+		gl.BufferData(target, capacity, nil, usage.glUsage())
+	case usage.glUsage() == gl.STREAM_DRAW:
+		// Orphan the old storage, the driver can keep drawing from it.
+		gl.BufferData(target, capacity, nil, usage.glUsage())
+	}
+
+	if size > 0 {
+		gl.BufferSubData(target, 0, size, data)
+	}
+
+	return capacity
+}
+
+// updateBuffer replaces a byte range of the buffer bound to 'target'.
+func updateBuffer(target uint32, offset, size int, data unsafe.Pointer) {
+	if size > 0 {
+		gl.BufferSubData(target, offset, size, data)
+	}
+}
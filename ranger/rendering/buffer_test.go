@@ -0,0 +1,69 @@
+package rendering
+
+import (
+	"testing"
+)
+
+func Test_Buffer_Capacity(t *testing.T) {
+	if got := bufferCapacity(0, 100, StaticUsage); got != 100 {
+		t.Errorf("Expected static capacity 100, got: %d", got)
+	}
+
+	if got := bufferCapacity(0, 100, DynamicUsage); got != 128 {
+		t.Errorf("Expected dynamic capacity 128, got: %d", got)
+	}
+
+	if got := bufferCapacity(128, 129, StreamUsage); got != 256 {
+		t.Errorf("Expected stream capacity 256, got: %d", got)
+	}
+
+	if got := bufferCapacity(0, 10, 0); got != 10 {
+		t.Errorf("Expected default usage to be static, got: %d", got)
+	}
+}
+
+func Test_Buffer_ChangedRange(t *testing.T) {
+	first, count, full := changedRange(30, 45, DynamicUsage)
+	if full || first != 30 || count != 15 {
+		t.Errorf("Expected appended range 30+15, got: %d+%d full %v", first, count, full)
+	}
+
+	_, count, full = changedRange(45, 30, DynamicUsage)
+	if !full || count != 30 {
+		t.Errorf("Expected a full upload of 30 after shrinking, got: %d full %v", count, full)
+	}
+
+	_, _, full = changedRange(30, 45, StaticUsage)
+	if !full {
+		t.Errorf("Expected static meshes to upload fully")
+	}
+
+	_, _, full = changedRange(30, 45, StreamUsage)
+	if !full {
+		t.Errorf("Expected stream meshes to upload fully")
+	}
+}
+
+func Test_VectorAtlas_Usage(t *testing.T) {
+	va := NewVectorUniformAtlas(false)
+	if va.Usage() != DynamicUsage {
+		t.Errorf("Expected a non static atlas to be dynamic, got: %d", va.Usage())
+	}
+
+	va.SetUsage(StaticUsage)
+	if !va.IsStatic() {
+		t.Errorf("Expected atlas to be static")
+	}
+
+	va.AddVertex(1, 2, 3)
+	va.AddIndex(0)
+	va.SetVertex(0, 4, 5, 6)
+	if va.mesh.Vertices[0] != 4 || va.mesh.Vertices[2] != 6 {
+		t.Errorf("Expected vertex 4,5,6, got: %v", va.mesh.Vertices[:3])
+	}
+
+	va.Reset()
+	if va.ComponentCount != 0 || va.Idx != 0 || len(va.mesh.Vertices) != 0 || len(va.mesh.Indices) != 0 {
+		t.Errorf("Expected an empty atlas after Reset")
+	}
+}
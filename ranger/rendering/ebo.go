@@ -5,6 +5,7 @@ import (
 	"unsafe"
 
 	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/ranger/graphics"
 )
 
 // EBO represents a shader's EBO features.
@@ -13,6 +14,9 @@ type EBO struct {
 	genBound bool
 
 	eboID uint32 // GLuint
+
+	// capacity of the buffer's storage in bytes
+	capacity int
 }
 
 // NewEBO creates a empty EBO
@@ -22,20 +26,57 @@ func NewEBO() *EBO {
 	return b
 }
 
-// GenBuffer generates a buffer id for buffer data.
+// GenBuffer generates a buffer id for buffer data, once.
 // Call this BEFORE you call Bind.
 func (b *EBO) GenBuffer() {
+	if b.genBound {
+		return
+	}
 	gl.GenBuffers(1, &b.eboID)
+	graphics.Resources.Created(graphics.BufferResource, b.eboID, "EBO")
 	b.genBound = true
 }
 
-// Bind binds the buffer id against the mesh indices
-func (b *EBO) Bind(m *Mesh) {
+// Delete deletes the buffer. It can be generated again.
+func (b *EBO) Delete() {
+	if !b.genBound {
+		return
+	}
+	gl.DeleteBuffers(1, &b.eboID)
+	graphics.GLState.BufferDeleted(b.eboID)
+	graphics.Resources.Deleted(graphics.BufferResource, b.eboID)
+	b.eboID = 0
+	b.capacity = 0
+	b.genBound = false
+}
+
+const indexSize = int(unsafe.Sizeof(uint32(0)))
+
+// Bind binds the buffer id against the mesh indices and uploads all of
+// them. It returns true if the buffer's storage was (re)allocated.
+func (b *EBO) Bind(m *Mesh) bool {
 	if !b.genBound {
 		panic("An EBO buffer ID has not been generated. Call GenBuffer first.")
 	}
 
-	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, b.eboID)
-	indicesCount := len(m.Indices) * int(unsafe.Sizeof(uint32(0)))
-	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, indicesCount, gl.Ptr(m.Indices), gl.STATIC_DRAW)
+	graphics.GLState.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, b.eboID)
+
+	previous := b.capacity
+	b.capacity = uploadBuffer(gl.ELEMENT_ARRAY_BUFFER, b.capacity, len(m.Indices)*indexSize, ptr(m.Indices), m.Usage)
+
+	return b.capacity != previous
+}
+
+// Update uploads 'count' indices starting at 'first'. If they don't fit
+// the buffer everything is uploaded as with Bind, and true is returned.
+// The EBO must be bound, typically by its VAO.
+func (b *EBO) Update(m *Mesh, first, count int) bool {
+	if (first+count)*indexSize > b.capacity {
+		return b.Bind(m)
+	}
+
+	graphics.GLState.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, b.eboID)
+	updateBuffer(gl.ELEMENT_ARRAY_BUFFER, first*indexSize, count*indexSize, ptr(m.Indices[first:first+count]))
+
+	return false
 }
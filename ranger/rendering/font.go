@@ -0,0 +1,48 @@
+// Package rendering defines Font features of text rendering.
+package rendering
+
+import (
+	"github.com/wdevore/ranger/fonts"
+)
+
+// Font is a GlyphAtlas whose pages have been uploaded as textures.
+type Font struct {
+	Atlas *fonts.GlyphAtlas
+	Pages []*Texture
+}
+
+// NewFont uploads a GlyphAtlas' pages.
+func NewFont(atlas *fonts.GlyphAtlas, options TextureOptions) (*Font, error) {
+	f := new(Font)
+	f.Atlas = atlas
+
+	for _, page := range atlas.Pages {
+		tex := NewTexture(options)
+		err := tex.Upload(page)
+		if err != nil {
+			f.Delete()
+			return nil, err
+		}
+		f.Pages = append(f.Pages, tex)
+	}
+
+	return f, nil
+}
+
+// Delete deletes the page textures.
+func (f *Font) Delete() {
+	for _, page := range f.Pages {
+		page.Delete()
+	}
+	f.Pages = nil
+}
+
+// LoadBMFont loads an AngelCode .fnt (text or binary) and uploads its pages.
+func LoadBMFont(path string, options TextureOptions) (*Font, error) {
+	atlas, err := fonts.LoadBMFont(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFont(atlas, options)
+}
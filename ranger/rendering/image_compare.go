@@ -0,0 +1,75 @@
+// Package rendering defines golden image helpers.
+package rendering
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+)
+
+// CompareImages returns how many pixels differ by more than 'tolerance'
+// in any channel. Images of different sizes are an error.
+func CompareImages(got, want image.Image, tolerance uint8) (int, error) {
+	if got.Bounds().Size() != want.Bounds().Size() {
+		return 0, fmt.Errorf("image is %v, expected %v", got.Bounds().Size(), want.Bounds().Size())
+	}
+
+	g := toNRGBA(got)
+	w := toNRGBA(want)
+
+	different := 0
+	for i := 0; i < len(g.Pix); i += 4 {
+		for c := 0; c < 4; c++ {
+			d := int(g.Pix[i+c]) - int(w.Pix[i+c])
+			if d < 0 {
+				d = -d
+			}
+			if d > int(tolerance) {
+				different++
+				break
+			}
+		}
+	}
+
+	return different, nil
+}
+
+// toNRGBA converts to straight alpha, as PNGs and SoftwareBackends store.
+func toNRGBA(img image.Image) *image.NRGBA {
+	if nrgba, ok := img.(*image.NRGBA); ok && nrgba.Rect.Min == (image.Point{}) {
+		return nrgba
+	}
+
+	nrgba := image.NewNRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(nrgba, nrgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	return nrgba
+}
+
+// LoadPNG reads a PNG, for example, a golden image.
+func LoadPNG(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return png.Decode(file)
+}
+
+// SavePNG writes an image as a PNG, for example, to update a golden image.
+func SavePNG(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = png.Encode(file, img)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,129 @@
+// Package rendering defines per instance data of instanced shapes.
+package rendering
+
+import (
+	"math"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/ranger/graphics"
+)
+
+// Instance is one copy of an instanced shape. Its transform is applied
+// in the shape's local space before the draw's mvp.
+type Instance struct {
+	X, Y float32
+	// Rotation in radians
+	Rotation float32
+	ScaleX   float32
+	ScaleY   float32
+
+	Color graphics.Colors
+}
+
+// InstanceBuffer holds the Instances of a shape of a VectorObject and
+// uploads them, as InstanceFormat, each time they are drawn.
+type InstanceBuffer struct {
+	Instances []Instance
+
+	mesh Mesh
+
+	// The VAO combines the object's vertices with the instance data.
+	genBound bool
+	vaoID    uint32
+	object   *VectorObject
+}
+
+// NewInstanceBuffer creates an empty buffer with room for 'capacity'
+// instances before growing.
+func NewInstanceBuffer(capacity int) *InstanceBuffer {
+	ib := new(InstanceBuffer)
+	ib.Instances = make([]Instance, 0, capacity)
+	ib.mesh.Format = InstanceFormat
+	ib.mesh.Usage = StreamUsage
+	return ib
+}
+
+// Add appends a white, unscaled and unrotated instance at x,y and returns
+// its index.
+func (ib *InstanceBuffer) Add(x, y float32) int {
+	ib.Instances = append(ib.Instances, Instance{X: x, Y: y, ScaleX: 1.0, ScaleY: 1.0})
+	ib.Instances[len(ib.Instances)-1].Color.SetFromColors(graphics.White)
+	return len(ib.Instances) - 1
+}
+
+// Remove removes an instance by moving the last instance into its place.
+func (ib *InstanceBuffer) Remove(index int) {
+	last := len(ib.Instances) - 1
+	ib.Instances[index] = ib.Instances[last]
+	ib.Instances = ib.Instances[:last]
+}
+
+// Clear removes all instances.
+func (ib *InstanceBuffer) Clear() {
+	ib.Instances = ib.Instances[:0]
+}
+
+// Len returns how many instances there are.
+func (ib *InstanceBuffer) Len() int {
+	return len(ib.Instances)
+}
+
+// Delete deletes the VAO and instance buffer, not the object's buffers.
+// Drawing creates them again.
+func (ib *InstanceBuffer) Delete() {
+	if ib.genBound {
+		deleteVertexArray(ib.vaoID)
+		ib.vaoID = 0
+		ib.genBound = false
+	}
+	ib.mesh.Delete()
+	ib.object = nil
+}
+
+// pack converts the instances to InstanceFormat.
+func (ib *InstanceBuffer) pack() {
+	ib.mesh.Vertices = ib.mesh.Vertices[:0]
+
+	for i := range ib.Instances {
+		in := &ib.Instances[i]
+
+		s := float32(math.Sin(float64(in.Rotation)))
+		c := float32(math.Cos(float64(in.Rotation)))
+
+		// [translate] x [rotate] x [scale]
+		ib.mesh.Vertices = append(ib.mesh.Vertices,
+			c*in.ScaleX, -s*in.ScaleY, in.X,
+			s*in.ScaleX, c*in.ScaleY, in.Y,
+			in.Color.R, in.Color.G, in.Color.B, in.Color.A)
+	}
+}
+
+// bind uploads the instances and binds a VAO of the object's vertices and
+// the instances. The object must have been bound (uploaded).
+func (ib *InstanceBuffer) bind(vo *VectorObject) {
+	ib.pack()
+
+	if !ib.genBound {
+		ib.vaoID = genVertexArray()
+		ib.genBound = true
+	}
+	ib.mesh.vbo.GenBuffer()
+
+	graphics.GLState.BindVertexArray(ib.vaoID)
+
+	if ib.object != vo {
+		ib.object = vo
+		m := vo.vao.mesh
+
+		graphics.GLState.BindBuffer(gl.ARRAY_BUFFER, m.vbo.vboID)
+		m.VertexFormat().apply(m.VertexCount())
+		graphics.GLState.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, m.ebo.eboID)
+
+		ib.mesh.vbo.Bind(&ib.mesh)
+		ib.mesh.VertexFormat().apply(ib.Len())
+	} else {
+		ib.mesh.vbo.Bind(&ib.mesh)
+	}
+
+	graphics.GLState.BindBuffer(gl.ARRAY_BUFFER, 0)
+}
@@ -0,0 +1,45 @@
+package rendering
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wdevore/ranger/rmath"
+)
+
+func Test_InstanceBuffer_Pack(t *testing.T) {
+	ib := NewInstanceBuffer(4)
+
+	ib.Add(1.0, 2.0)
+	i := ib.Add(10.0, 20.0)
+	ib.Instances[i].Rotation = math.Pi / 2.0
+	ib.Instances[i].ScaleX = 2.0
+	ib.Instances[i].Color.A = 0.5
+
+	ib.pack()
+
+	if len(ib.mesh.Vertices) != 2*InstanceFormat.ComponentCount() {
+		t.Errorf("Expected %d components, got: %d", 2*InstanceFormat.ComponentCount(), len(ib.mesh.Vertices))
+	}
+
+	// Apply the second instance's rows to 1,0 as the shader does.
+	row := ib.mesh.Vertices[InstanceFormat.ComponentCount():]
+	x := row[0]*1.0 + row[2]
+	y := row[3]*1.0 + row[5]
+
+	var expected rmath.Vector3
+	expected.Set3Components(10.0, 22.0, 0.0)
+	got := rmath.NewVector3With2Components(x, y)
+	if !got.EqEpsilon(&expected) {
+		t.Errorf("Expected %v, got: %v", expected, got)
+	}
+
+	if row[9] != 0.5 {
+		t.Errorf("Expected alpha 0.5, got: %f", row[9])
+	}
+
+	ib.Remove(0)
+	if ib.Len() != 1 || ib.Instances[0].X != 10.0 {
+		t.Errorf("Expected the last instance to replace the removed one, got: %v", ib.Instances)
+	}
+}
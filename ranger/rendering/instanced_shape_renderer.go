@@ -0,0 +1,58 @@
+// Package rendering defines an instanced VectorShape renderer.
+package rendering
+
+import (
+	"github.com/wdevore/ranger/graphics"
+	"github.com/wdevore/ranger/rmath"
+)
+
+// InstancedShapeRenderer draws a VectorShape once per Instance of an
+// InstanceBuffer with a single draw call. It expects a shader with:
+//   attribute 0: vec3 position
+//   attribute 4: vec3 instance transform row 0
+//   attribute 5: vec3 instance transform row 1
+//   attribute 6: vec4 instance colour
+//   uniform mat4 mvp
+//   uniform vec4 color    multiplied with each instance's colour
+type InstancedShapeRenderer struct {
+	shader *Shader
+
+	mvp   Uniform
+	color Uniform
+}
+
+// NewInstancedShapeRenderer creates a renderer using an instanced vector
+// shader. You must call Construct before drawing.
+func NewInstancedShapeRenderer(shader *Shader) *InstancedShapeRenderer {
+	ir := new(InstancedShapeRenderer)
+	ir.shader = shader
+	return ir
+}
+
+// Construct resolves the shader's uniforms. The shader must already be loaded.
+func (ir *InstancedShapeRenderer) Construct() error {
+	uniforms, err := ir.shader.Uniforms("mvp", "color")
+	if err != nil {
+		return err
+	}
+	ir.mvp, ir.color = uniforms[0], uniforms[1]
+
+	return nil
+}
+
+// Draw renders every instance of a shape of the vector object. The object
+// must have been bound (uploaded).
+func (ir *InstancedShapeRenderer) Draw(vo *VectorObject, shape *VectorShape, instances *InstanceBuffer, mvp *rmath.Matrix4, color *graphics.Colors) {
+	if instances.Len() == 0 {
+		return
+	}
+
+	ir.shader.Use()
+
+	ir.mvp.SetMatrix4(mvp)
+	ir.color.SetColor(color)
+
+	instances.bind(vo)
+	vo.vao.RenderInstanced(shape, instances.Len())
+	graphics.GLState.BindVertexArray(0)
+}
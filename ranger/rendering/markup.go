@@ -0,0 +1,149 @@
+// Package rendering defines rich text markup.
+package rendering
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wdevore/ranger/fonts"
+	"github.com/wdevore/ranger/graphics"
+)
+
+// MarkupSpan is a piece of rich text in one style.
+type MarkupSpan struct {
+	Text string
+	// Color overrides the text's colour. Nil keeps it.
+	Color *graphics.Colors
+	// Scale multiplies the text's size.
+	Scale float32
+	// Icon names a SpriteSheet frame drawn inline instead of Text.
+	Icon string
+}
+
+// markupStyle is an open tag.
+type markupStyle struct {
+	tag   string
+	color *graphics.Colors
+	scale float32
+}
+
+// ParseMarkup splits rich text into spans. Tags are:
+//   [color=#ff8000]orange[/color]   any hex accepted by SetColorFromHex
+//   [size=1.5]bigger[/size]         multiplies the size
+//   [icon=coin]                     an inline SpriteSheet frame
+//   [[                              a literal '['
+// Color and size tags nest and must be closed in reverse order.
+func ParseMarkup(markup string) ([]MarkupSpan, error) {
+	spans := []MarkupSpan{}
+	stack := []markupStyle{{scale: 1.0}}
+
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() == 0 {
+			return
+		}
+		top := stack[len(stack)-1]
+		spans = append(spans, MarkupSpan{Text: text.String(), Color: top.color, Scale: top.scale})
+		text.Reset()
+	}
+
+	for i := 0; i < len(markup); i++ {
+		c := markup[i]
+		if c != '[' {
+			text.WriteByte(c)
+			continue
+		}
+
+		if i+1 < len(markup) && markup[i+1] == '[' {
+			text.WriteByte('[')
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(markup[i:], ']')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated tag at %d", i)
+		}
+
+		tag := markup[i+1 : i+end]
+		i += end
+
+		flush()
+
+		if strings.HasPrefix(tag, "/") {
+			name := tag[1:]
+			top := stack[len(stack)-1]
+			if len(stack) == 1 || top.tag != name {
+				return nil, fmt.Errorf("unexpected closing tag: [%s]", tag)
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		name, value := tag, ""
+		if eq := strings.IndexByte(tag, '='); eq >= 0 {
+			name, value = tag[:eq], tag[eq+1:]
+		}
+
+		style := stack[len(stack)-1]
+		style.tag = name
+
+		switch name {
+		case "color":
+			color, err := graphics.NewColors().SetColorFromHex(value)
+			if err != nil {
+				return nil, err
+			}
+			style.color = color
+		case "size":
+			scale, err := strconv.ParseFloat(value, 32)
+			if err != nil || scale <= 0.0 {
+				return nil, fmt.Errorf("invalid size: '%s'", value)
+			}
+			style.scale *= float32(scale)
+		case "icon":
+			if value == "" {
+				return nil, fmt.Errorf("icon tag without a name")
+			}
+			spans = append(spans, MarkupSpan{Icon: value, Color: style.color, Scale: style.scale})
+			continue
+		default:
+			return nil, fmt.Errorf("unknown tag: [%s]", tag)
+		}
+
+		stack = append(stack, style)
+	}
+
+	if len(stack) > 1 {
+		return nil, fmt.Errorf("unclosed tag: [%s]", stack[len(stack)-1].tag)
+	}
+
+	flush()
+
+	return spans, nil
+}
+
+// MarkupRuns converts spans to layout runs tagged with their span's index.
+func MarkupRuns(spans []MarkupSpan) []fonts.Run {
+	runs := make([]fonts.Run, len(spans))
+
+	for i, s := range spans {
+		runs[i] = fonts.Run{Text: s.Text, Scale: s.Scale, Icon: s.Icon, Tag: i}
+	}
+
+	return runs
+}
+
+// MarkupColors returns each span's colour indexed like MarkupRuns' tags,
+// ready for TextMesh.TagColors.
+func MarkupColors(spans []MarkupSpan) []*graphics.Colors {
+	colors := make([]*graphics.Colors, len(spans))
+
+	for i, s := range spans {
+		colors[i] = s.Color
+	}
+
+	return colors
+}
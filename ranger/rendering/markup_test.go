@@ -0,0 +1,64 @@
+package rendering
+
+import (
+	"testing"
+)
+
+func Test_ParseMarkup(t *testing.T) {
+	spans, err := ParseMarkup("Take [color=#ff0000]the [size=2]red[/size] key[/color] [icon=key] [[ok]")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"Take ", "the ", "red", " key", " ", "", " [ok]"}
+	if len(spans) != len(expected) {
+		t.Fatalf("Expected %d spans, got: %d", len(expected), len(spans))
+	}
+
+	for i, e := range expected {
+		if spans[i].Text != e {
+			t.Errorf("Expected span %d '%s', got: '%s'", i, e, spans[i].Text)
+		}
+	}
+
+	if spans[0].Color != nil || spans[0].Scale != 1.0 {
+		t.Errorf("Expected an unstyled first span, got: %v", spans[0])
+	}
+
+	red := spans[2]
+	if red.Color == nil || red.Color.R != 1.0 || red.Color.G != 0.0 || red.Scale != 2.0 {
+		t.Errorf("Expected red at scale 2, got: %v %f", red.Color, red.Scale)
+	}
+
+	if spans[3].Color == nil || spans[3].Scale != 1.0 {
+		t.Errorf("Expected ' key' red at scale 1, got: %v %f", spans[3].Color, spans[3].Scale)
+	}
+
+	if spans[5].Icon != "key" {
+		t.Errorf("Expected icon 'key', got: '%s'", spans[5].Icon)
+	}
+
+	runs := MarkupRuns(spans)
+	if runs[2].Tag != 2 || runs[2].Scale != 2.0 || runs[5].Icon != "key" {
+		t.Errorf("Expected runs tagged by span, got: %v", runs)
+	}
+}
+
+func Test_ParseMarkup_Errors(t *testing.T) {
+	bad := []string{
+		"[color=red]x[/color]",
+		"[size=big]x[/size]",
+		"[bold]x[/bold]",
+		"[color=#ffffff]x",
+		"x[/color]",
+		"[size=2][color=#ffffff]x[/size][/color]",
+		"[icon=]",
+		"[size=2",
+	}
+
+	for _, markup := range bad {
+		if _, err := ParseMarkup(markup); err == nil {
+			t.Errorf("Expected an error for '%s'", markup)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 // Package rendering defines Mesh features of shaders.
 package rendering
 
+import (
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/ranger/graphics"
+)
+
 // Mesh combines a shader's VBO and EBO features.
 type Mesh struct {
 	// Vertices are for VBO
@@ -9,8 +14,19 @@ type Mesh struct {
 	// Indices are for EBO
 	Indices []uint32
 
+	// Format of the Vertices. Nil means PositionFormat.
+	Format *VertexFormat
+
+	// Usage of the buffers, StaticUsage by default. Dynamic and stream
+	// meshes can be changed and appended to after they are bound.
+	Usage BufferUsage
+
 	vbo VBO
 	ebo EBO
+
+	// How many vertex components and indices the buffers hold.
+	uploadedVertices int
+	uploadedIndices  int
 }
 
 // NewMesh creates a new Mesh object
@@ -21,14 +37,115 @@ func NewMesh() *Mesh {
 	return m
 }
 
-// Bind binds this Mesh to a VBO and EBO
-func (m *Mesh) Bind() {
-	m.vbo.Bind(m)
-	m.ebo.Bind(m)
+// Bind binds this Mesh to a VBO and EBO and uploads all of it. It returns
+// true if either buffer was (re)allocated.
+func (m *Mesh) Bind() bool {
+	grewVertices := m.vbo.Bind(m)
+	grewIndices := m.ebo.Bind(m)
+
+	m.uploadedVertices = len(m.Vertices)
+	m.uploadedIndices = len(m.Indices)
+
+	return grewVertices || grewIndices
+}
+
+// Update uploads what changed in length since the last upload: appended
+// vertices and indices are written after the existing ones. Shrinking, or
+// a static or stream Mesh, uploads everything. The EBO must be bound,
+// typically by its VAO. It returns true if the attribute layout must be
+// recorded again because separate streams moved.
+func (m *Mesh) Update() bool {
+	relayout := !m.VertexFormat().Interleaved && len(m.Vertices) != m.uploadedVertices
+
+	first, count, full := changedRange(m.uploadedVertices, len(m.Vertices), m.Usage)
+	if full || relayout {
+		m.vbo.Bind(m)
+	} else {
+		m.vbo.Update(m, first, count)
+	}
+
+	first, count, full = changedRange(m.uploadedIndices, len(m.Indices), m.Usage)
+	if full {
+		m.ebo.Bind(m)
+	} else {
+		m.ebo.Update(m, first, count)
+	}
+
+	m.uploadedVertices = len(m.Vertices)
+	m.uploadedIndices = len(m.Indices)
+
+	return relayout
+}
+
+// UpdateVertices uploads 'count' vertices starting at vertex 'first' after
+// they were changed in place. Formats with separate streams upload all
+// vertices.
+func (m *Mesh) UpdateVertices(first, count int) {
+	format := m.VertexFormat()
+	if !format.Interleaved {
+		m.vbo.Bind(m)
+		return
+	}
+
+	components := format.ComponentCount()
+	m.vbo.Update(m, first*components, count*components)
+}
+
+// UpdateIndices uploads 'count' indices starting at 'first' after they were
+// changed in place. The EBO must be bound, typically by its VAO.
+func (m *Mesh) UpdateIndices(first, count int) {
+	m.ebo.Update(m, first, count)
+}
+
+// changedRange returns the range of a buffer to upload after its data
+// changed length from 'uploaded' to 'length', or full if it must all be.
+func changedRange(uploaded, length int, usage BufferUsage) (first, count int, full bool) {
+	if usage.glUsage() == gl.STATIC_DRAW || usage.glUsage() == gl.STREAM_DRAW || length < uploaded {
+		return 0, length, true
+	}
+	return uploaded, length - uploaded, false
 }
 
-// GenBuffers generates buffers for VBO and EBO
+// GenBuffers generates buffers for VBO and EBO, if they haven't been.
 func (m *Mesh) GenBuffers() {
 	m.vbo.GenBuffer()
 	m.ebo.GenBuffer()
 }
+
+// Delete deletes the VBO and EBO buffers. The vertices and indices are
+// kept so the Mesh can be bound again.
+func (m *Mesh) Delete() {
+	m.vbo.Delete()
+	m.ebo.Delete()
+	m.uploadedVertices = 0
+	m.uploadedIndices = 0
+}
+
+// VertexFormat returns the Mesh's format.
+func (m *Mesh) VertexFormat() *VertexFormat {
+	if m.Format == nil {
+		return PositionFormat
+	}
+	return m.Format
+}
+
+// VertexCount returns how many vertices the Mesh has.
+func (m *Mesh) VertexCount() int {
+	return len(m.Vertices) / m.VertexFormat().ComponentCount()
+}
+
+// bindLayout uploads the Mesh and records its attribute layout in a VAO.
+func (m *Mesh) bindLayout(vaoID uint32) {
+	graphics.GLState.BindVertexArray(vaoID)
+
+	m.Bind()
+	m.VertexFormat().apply(m.VertexCount())
+
+	// Note that this is allowed, the call to glVertexAttribPointer registered VBO as the currently bound
+	// vertex buffer object so afterwards we can safely unbind
+	graphics.GLState.BindBuffer(gl.ARRAY_BUFFER, 0)
+
+	// Unbind VAO (it's always a good thing to unbind any buffer/array to prevent strange bugs),
+	// remember: do NOT unbind the EBO, keep it bound to this VAO
+	graphics.GLState.BindVertexArray(0)
+}
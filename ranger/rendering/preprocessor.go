@@ -0,0 +1,215 @@
+// Package rendering defines the GLSL preprocessor.
+package rendering
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Preprocessor expands shader code before it is compiled:
+//   #include "common.glsl"   inserts a file read from the same sources.
+//                            Each file is included once.
+//   Defines                  are inserted after #version as #define lines.
+//   Version                  replaces (or adds) the #version line, for
+//                            example, "330 core". Empty keeps the code's.
+type Preprocessor struct {
+	Defines map[string]string
+	Version string
+
+	sources []ShaderSource
+}
+
+// SourceLine is where a line of preprocessed code came from.
+type SourceLine struct {
+	File string
+	Line int
+}
+
+// ProcessedShader is preprocessed code and the origin of each line.
+type ProcessedShader struct {
+	Name string
+	Code string
+	// Lines[i] is the origin of line i+1 of Code.
+	Lines []SourceLine
+	// Files read, the shader's file first.
+	Files []string
+
+	// hasVersion is true if the code has a #version line.
+	hasVersion bool
+}
+
+var includeRexp = regexp.MustCompile(`^\s*#\s*include\s+["<]([^">]+)[">]`)
+var versionRexp = regexp.MustCompile(`^\s*#\s*version\b`)
+
+// NewPreprocessor creates a Preprocessor reading files from the sources.
+func NewPreprocessor(sources []ShaderSource) *Preprocessor {
+	p := new(Preprocessor)
+	p.Defines = map[string]string{}
+	p.sources = sources
+	return p
+}
+
+// Process reads a shader file and expands it.
+func (p *Preprocessor) Process(name string) (*ProcessedShader, error) {
+	ps := &ProcessedShader{Name: name}
+
+	body := &ProcessedShader{Name: name}
+	version := ""
+
+	err := p.expand(name, body, map[string]bool{}, []string{}, &version)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Version != "" {
+		version = "#version " + p.Version
+	}
+
+	if version != "" {
+		ps.add(version, SourceLine{File: name, Line: 1})
+		ps.hasVersion = true
+	}
+
+	// Sorted so the code, and the shader cache key, is stable.
+	names := make([]string, 0, len(p.Defines))
+	for define := range p.Defines {
+		names = append(names, define)
+	}
+	sort.Strings(names)
+
+	for i, define := range names {
+		ps.add(strings.TrimSpace("#define "+define+" "+p.Defines[define]), SourceLine{File: "<defines>", Line: i + 1})
+	}
+
+	ps.Lines = append(ps.Lines, body.Lines...)
+	ps.Code += body.Code
+	ps.Files = body.Files
+
+	return ps, nil
+}
+
+// expand appends a file's lines to 'ps' replacing #includes. 'stack' is
+// the chain of files being included, to detect cycles.
+func (p *Preprocessor) expand(name string, ps *ProcessedShader, included map[string]bool, stack []string, version *string) error {
+	for _, parent := range stack {
+		if parent == name {
+			return fmt.Errorf("include cycle: %s -> %s", strings.Join(stack, " -> "), name)
+		}
+	}
+
+	if included[name] {
+		return nil
+	}
+	included[name] = true
+
+	code, err := readShader(name, p.sources)
+	if err != nil {
+		if len(stack) > 0 {
+			return fmt.Errorf("%s: %s", stack[len(stack)-1], err.Error())
+		}
+		return err
+	}
+
+	ps.Files = append(ps.Files, name)
+	stack = append(stack, name)
+
+	for i, line := range strings.Split(strings.TrimRight(code, "\n"), "\n") {
+		if versionRexp.MatchString(line) {
+			// Only the top file may choose the version.
+			if len(stack) == 1 && *version == "" {
+				*version = strings.TrimSpace(line)
+			}
+			continue
+		}
+
+		if m := includeRexp.FindStringSubmatch(line); m != nil {
+			err = p.expand(m[1], ps, included, stack, version)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		ps.add(line, SourceLine{File: name, Line: i + 1})
+	}
+
+	return nil
+}
+
+func (ps *ProcessedShader) add(line string, origin SourceLine) {
+	ps.Code += line + "\n"
+	ps.Lines = append(ps.Lines, origin)
+}
+
+// Origin returns where a line (1 based) of the code came from.
+func (ps *ProcessedShader) Origin(line int) (SourceLine, bool) {
+	if line < 1 || line > len(ps.Lines) {
+		return SourceLine{}, false
+	}
+	return ps.Lines[line-1], true
+}
+
+// Compiler logs refer to lines as "0(12)" (NVIDIA) or "0:12" (Mesa, AMD
+// and Intel).
+var logLineRexp = regexp.MustCompile(`\b\d+\((\d+)\)|\b\d+:(\d+)`)
+
+// MapLog rewrites a compiler log's line references to file:line of the
+// original files.
+func (ps *ProcessedShader) MapLog(log string) string {
+	lines := strings.Split(log, "\n")
+
+	for i, line := range lines {
+		loc := logLineRexp.FindStringSubmatchIndex(line)
+		if loc == nil {
+			continue
+		}
+
+		// Either group 1 or group 2 matched.
+		start, end := loc[2], loc[3]
+		if start < 0 {
+			start, end = loc[4], loc[5]
+		}
+
+		n, err := strconv.Atoi(line[start:end])
+		if err != nil {
+			continue
+		}
+
+		origin, ok := ps.Origin(n)
+		if !ok {
+			continue
+		}
+
+		lines[i] = line[:loc[0]] + fmt.Sprintf("%s:%d", origin.File, origin.Line) + line[loc[1]:]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// GLSLVersion returns the #version matching an OpenGL context version,
+// for example, 3.3 -> "330 core".
+func GLSLVersion(major, minor int) string {
+	switch {
+	case major > 3 || (major == 3 && minor >= 3):
+		return fmt.Sprintf("%d%d0 core", major, minor)
+	case major == 3:
+		return []string{"130", "140", "150"}[minor]
+	case major == 2 && minor == 1:
+		return "120"
+	}
+	return "110"
+}
+
+// ensureVersion adds a #version line if the code doesn't have one.
+func (ps *ProcessedShader) ensureVersion(version string) {
+	if ps.hasVersion {
+		return
+	}
+
+	ps.Code = "#version " + version + "\n" + ps.Code
+	ps.Lines = append([]SourceLine{{File: ps.Name, Line: 0}}, ps.Lines...)
+	ps.hasVersion = true
+}
@@ -0,0 +1,124 @@
+package rendering
+
+import (
+	"strings"
+	"testing"
+)
+
+var preprocessorSources = []ShaderSource{MapSource{
+	"main.frag":     "#version 330 core\n#include \"lighting.glsl\"\n#include \"color.glsl\"\nvoid main() {}\n",
+	"lighting.glsl": "#include \"color.glsl\"\nfloat light;\n",
+	"color.glsl":    "vec4 tint;\n",
+	"plain.vert":    "void main() {}\n",
+	"cycle_a.glsl":  "#include \"cycle_b.glsl\"\n",
+	"cycle_b.glsl":  "#include \"cycle_a.glsl\"\n",
+	"broken.frag":   "#include \"missing.glsl\"\n",
+}}
+
+func Test_Preprocessor_Include(t *testing.T) {
+	pp := NewPreprocessor(preprocessorSources)
+	pp.Defines["MAX_LIGHTS"] = "4"
+	pp.Defines["SHADOWS"] = ""
+
+	ps, err := pp.Process("main.frag")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "#version 330 core\n#define MAX_LIGHTS 4\n#define SHADOWS\nvec4 tint;\nfloat light;\nvoid main() {}\n"
+	if ps.Code != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, ps.Code)
+	}
+
+	origins := []SourceLine{
+		{"main.frag", 1}, {"<defines>", 1}, {"<defines>", 2},
+		{"color.glsl", 1}, {"lighting.glsl", 2}, {"main.frag", 4},
+	}
+	for i, e := range origins {
+		if o, _ := ps.Origin(i + 1); o != e {
+			t.Errorf("Expected line %d from %v, got: %v", i+1, e, o)
+		}
+	}
+}
+
+func Test_Preprocessor_Version(t *testing.T) {
+	pp := NewPreprocessor(preprocessorSources)
+	pp.Version = "410 core"
+
+	ps, err := pp.Process("main.frag")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(ps.Code, "#version 410 core\n") || strings.Count(ps.Code, "#version") != 1 {
+		t.Errorf("Expected a single #version 410 core, got:\n%s", ps.Code)
+	}
+
+	pp.Version = ""
+	ps, err = pp.Process("plain.vert")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ps.ensureVersion(GLSLVersion(3, 3))
+	if ps.Code != "#version 330 core\nvoid main() {}\n" {
+		t.Errorf("Expected the context's version to be added, got:\n%s", ps.Code)
+	}
+
+	if o, _ := ps.Origin(2); o.File != "plain.vert" || o.Line != 1 {
+		t.Errorf("Expected line 2 from plain.vert:1, got: %v", o)
+	}
+}
+
+func Test_Preprocessor_Errors(t *testing.T) {
+	pp := NewPreprocessor(preprocessorSources)
+
+	_, err := pp.Process("cycle_a.glsl")
+	if err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("Expected an include cycle error, got: %v", err)
+	}
+
+	_, err = pp.Process("broken.frag")
+	if err == nil || !strings.Contains(err.Error(), "broken.frag") || !strings.Contains(err.Error(), "missing.glsl") {
+		t.Errorf("Expected an error naming broken.frag and missing.glsl, got: %v", err)
+	}
+}
+
+func Test_Preprocessor_MapLog(t *testing.T) {
+	pp := NewPreprocessor(preprocessorSources)
+
+	ps, err := pp.Process("main.frag")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mesa := ps.MapLog("0:3(7): error: syntax error")
+	if mesa != "lighting.glsl:2(7): error: syntax error" {
+		t.Errorf("Expected the Mesa line mapped, got: %s", mesa)
+	}
+
+	nvidia := ps.MapLog("0(2) : error C0000: syntax error")
+	if nvidia != "color.glsl:1 : error C0000: syntax error" {
+		t.Errorf("Expected the NVIDIA line mapped, got: %s", nvidia)
+	}
+
+	intel := ps.MapLog("ERROR: 0:4: 'x' : undeclared identifier")
+	if intel != "ERROR: main.frag:4: 'x' : undeclared identifier" {
+		t.Errorf("Expected the Intel line mapped, got: %s", intel)
+	}
+}
+
+func Test_GLSLVersion(t *testing.T) {
+	expected := map[[2]int]string{
+		{3, 3}: "330 core",
+		{4, 5}: "450 core",
+		{3, 2}: "150",
+		{2, 1}: "120",
+	}
+
+	for v, e := range expected {
+		if got := GLSLVersion(v[0], v[1]); got != e {
+			t.Errorf("Expected %d.%d -> '%s', got: '%s'", v[0], v[1], e, got)
+		}
+	}
+}
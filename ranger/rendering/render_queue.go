@@ -0,0 +1,137 @@
+// Package rendering defines a render command queue.
+package rendering
+
+import (
+	"sort"
+
+	"github.com/wdevore/ranger/graphics"
+	"github.com/wdevore/ranger/rmath"
+)
+
+// RenderCommand is one shape to draw. Commands are plain data, creating,
+// queueing and sorting them makes no GL calls.
+type RenderCommand struct {
+	// Layer is the primary sort key, lower layers are drawn first.
+	Layer int
+	// Depth orders commands within a layer, lower is drawn first.
+	Depth float32
+
+	Shader *Shader
+	Object *VectorObject
+	Shape  *VectorShape
+
+	// Transform is the model matrix accumulated during traversal. As the
+	// traversal starts with the view-projection it is the shader's mvp.
+	Transform rmath.Matrix4
+	Color     graphics.Colors
+	Blend     BlendMode
+}
+
+// RenderBackend executes sorted commands, for example, a GLQueueBackend.
+type RenderBackend interface {
+	Execute(commands []RenderCommand)
+}
+
+// RenderQueue collects the commands a scene traversal produces, sorts
+// them and hands them to a RenderBackend. Commands are sorted by Layer
+// then Depth. Commands with the same Layer and Depth keep their
+// submission order, without a depth test it decides what's on top.
+//
+// The queue is a VectorShapeDrawer, shapes drawn with Draw take the
+// queue's current Layer, Depth, Shader and Blend. It is also a Flusher
+// for RenderView.Flushers.
+type RenderQueue struct {
+	// State given to commands submitted with Draw.
+	Layer  int
+	Depth  float32
+	Shader *Shader
+	Blend  BlendMode
+
+	Backend RenderBackend
+
+	commands []RenderCommand
+	sorted   bool
+}
+
+// NewRenderQueue creates an empty queue whose Draw commands use 'shader'
+// (typically NewVectorShader) and alpha blending.
+func NewRenderQueue(shader *Shader, backend RenderBackend) *RenderQueue {
+	rq := new(RenderQueue)
+	rq.Shader = shader
+	rq.Blend = BlendAlpha
+	rq.Backend = backend
+	return rq
+}
+
+// Submit queues a copy of a command.
+func (rq *RenderQueue) Submit(command *RenderCommand) {
+	rq.commands = append(rq.commands, *command)
+	rq.sorted = false
+}
+
+// Draw queues a shape with the queue's current state.
+func (rq *RenderQueue) Draw(vo *VectorObject, shape *VectorShape, mvp *rmath.Matrix4, color *graphics.Colors) {
+	rq.commands = append(rq.commands, RenderCommand{
+		Layer:  rq.Layer,
+		Depth:  rq.Depth,
+		Shader: rq.Shader,
+		Object: vo,
+		Shape:  shape,
+		Color:  *color,
+		Blend:  rq.Blend,
+	})
+
+	rq.commands[len(rq.commands)-1].Transform.Set(mvp)
+
+	rq.sorted = false
+}
+
+// Len returns how many commands are queued.
+func (rq *RenderQueue) Len() int {
+	return len(rq.commands)
+}
+
+// Commands returns the queued commands, in submission order until Sort is
+// called. The slice is only valid until the queue changes.
+func (rq *RenderQueue) Commands() []RenderCommand {
+	return rq.commands
+}
+
+// Sort orders the commands for execution.
+func (rq *RenderQueue) Sort() {
+	if rq.sorted {
+		return
+	}
+
+	sort.SliceStable(rq.commands, func(i, j int) bool {
+		return rq.commands[i].before(&rq.commands[j])
+	})
+
+	rq.sorted = true
+}
+
+// before returns true if 'c' must be drawn before 'o'.
+func (c *RenderCommand) before(o *RenderCommand) bool {
+	if c.Layer != o.Layer {
+		return c.Layer < o.Layer
+	}
+	return c.Depth < o.Depth
+}
+
+// Flush sorts the commands, executes them with the Backend and clears the
+// queue.
+func (rq *RenderQueue) Flush() {
+	rq.Sort()
+
+	if rq.Backend != nil && len(rq.commands) > 0 {
+		rq.Backend.Execute(rq.commands)
+	}
+
+	rq.Reset()
+}
+
+// Reset discards the queued commands.
+func (rq *RenderQueue) Reset() {
+	rq.commands = rq.commands[:0]
+	rq.sorted = false
+}
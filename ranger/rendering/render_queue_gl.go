@@ -0,0 +1,84 @@
+// Package rendering defines the GL backend of the render command queue.
+package rendering
+
+import (
+	"github.com/wdevore/ranger/graphics"
+)
+
+// GLQueueBackend executes RenderCommands with GL, only changing the shader,
+// VAO and blending when they differ from the previous command's. Shaders
+// must have the vector shader's attributes and mvp and color uniforms.
+type GLQueueBackend struct {
+	// Commands whose shader's uniforms don't resolve are skipped.
+	uniforms *shapeUniformCache
+
+	// DrawCalls made by the last Execute.
+	DrawCalls int
+	// StateChanges (shader, VAO or blend) made by the last Execute.
+	StateChanges int
+}
+
+// NewGLQueueBackend creates a backend. Shaders must already be loaded when
+// their commands are executed.
+func NewGLQueueBackend() *GLQueueBackend {
+	gb := new(GLQueueBackend)
+	gb.uniforms = newShapeUniformCache("GLQueueBackend")
+	return gb
+}
+
+// Execute draws the commands in order and restores the Stage's alpha
+// blending. Commands without a shader, or whose shader lacks the
+// uniforms, are skipped.
+func (gb *GLQueueBackend) Execute(commands []RenderCommand) {
+	gb.DrawCalls = 0
+	gb.StateChanges = 0
+
+	var shader *Shader
+	var su *shapeUniforms
+	var object *VectorObject
+	blend := BlendAlpha
+
+	for i := range commands {
+		c := &commands[i]
+
+		if c.Shader == nil {
+			continue
+		}
+
+		if c.Shader != shader {
+			uniforms, err := gb.uniforms.get(c.Shader)
+			if err != nil {
+				continue
+			}
+			su = uniforms
+
+			shader = c.Shader
+			shader.Use()
+			gb.StateChanges++
+		}
+
+		if c.Object != object {
+			object = c.Object
+			object.Use()
+			gb.StateChanges++
+		}
+
+		if c.Blend != blend {
+			blend = c.Blend
+			blend.Apply()
+			gb.StateChanges++
+		}
+
+		su.mvp.SetMatrix4(&c.Transform)
+		su.color.SetColor(&c.Color)
+
+		object.Render(c.Shape)
+		gb.DrawCalls++
+	}
+
+	graphics.GLState.BindVertexArray(0)
+
+	if blend != BlendAlpha {
+		BlendAlpha.Apply()
+	}
+}
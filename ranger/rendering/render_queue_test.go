@@ -0,0 +1,106 @@
+package rendering
+
+import (
+	"testing"
+
+	"github.com/wdevore/ranger/graphics"
+	"github.com/wdevore/ranger/rmath"
+)
+
+type recordingBackend struct {
+	executed []RenderCommand
+}
+
+func (rb *recordingBackend) Execute(commands []RenderCommand) {
+	rb.executed = append(rb.executed, commands...)
+}
+
+func Test_RenderQueue_Sort(t *testing.T) {
+	vo, outline, filled := batchSquare()
+	other := NewVectorObject()
+	other.Construct()
+
+	a := NewShader("a.vert", "a.frag")
+	b := NewShader("b.vert", "b.frag")
+
+	backend := &recordingBackend{}
+	rq := NewRenderQueue(a, backend)
+
+	var mvp rmath.Matrix4
+	mvp.SetTranslate3Comp(1.0, 2.0, 0.0)
+
+	// Layer 1 is drawn after layer 0, in submission order.
+	rq.Layer = 1
+	rq.Draw(vo, outline, &mvp, graphics.White)
+	rq.Draw(vo, filled, &mvp, graphics.White)
+
+	// Submission order is kept regardless of shader, object or blend.
+	rq.Layer = 0
+	rq.Blend = BlendNone
+	rq.Submit(&RenderCommand{Shader: b, Object: vo, Shape: filled, Blend: BlendNone})
+	rq.Draw(other, filled, &mvp, graphics.White)
+	rq.Draw(vo, outline, &mvp, graphics.White)
+
+	// Depth orders within a layer.
+	rq.Blend = BlendAlpha
+	rq.Depth = -1.0
+	rq.Draw(vo, filled, &mvp, graphics.White)
+
+	rq.Flush()
+
+	if rq.Len() != 0 {
+		t.Errorf("Expected an empty queue after Flush, got: %d", rq.Len())
+	}
+
+	expected := []struct {
+		layer  int
+		shader *Shader
+		object *VectorObject
+		shape  *VectorShape
+	}{
+		{0, a, vo, filled},
+		{0, b, vo, filled},
+		{0, a, other, filled},
+		{0, a, vo, outline},
+		{1, a, vo, outline},
+		{1, a, vo, filled},
+	}
+
+	if len(backend.executed) != len(expected) {
+		t.Fatalf("Expected %d commands, got: %d", len(expected), len(backend.executed))
+	}
+
+	for i, e := range expected {
+		c := backend.executed[i]
+		if c.Layer != e.layer || c.Shader != e.shader || c.Object != e.object || c.Shape != e.shape {
+			t.Errorf("Expected command %d to be layer %d %v %v, got: layer %d depth %f", i, e.layer, e.shader == a, e.shape == filled, c.Layer, c.Depth)
+		}
+	}
+}
+
+func Test_RenderQueue_Draw(t *testing.T) {
+	vo, outline, _ := batchSquare()
+
+	rq := NewRenderQueue(NewShader("a.vert", "a.frag"), nil)
+	rq.Blend = BlendAdditive
+
+	var mvp rmath.Matrix4
+	mvp.SetTranslate3Comp(5.0, 6.0, 0.0)
+	color := graphics.NewColors().SetFromInts(255, 0, 0, 128)
+
+	rq.Draw(vo, outline, &mvp, color)
+
+	// The command keeps copies.
+	mvp.ToIdentity()
+	color.R = 0.0
+
+	c := rq.Commands()[0]
+	var v rmath.Vector3
+	v.Mul(&c.Transform)
+	if v.X != 5.0 || v.Y != 6.0 {
+		t.Errorf("Expected transform to translate by 5,6, got: %v", v)
+	}
+	if c.Color.R != 1.0 || c.Blend != BlendAdditive {
+		t.Errorf("Expected an additive red command, got: %v %d", c.Color, c.Blend)
+	}
+}
@@ -0,0 +1,122 @@
+// Package rendering defines signed distance field text rendering.
+package rendering
+
+import (
+	"github.com/wdevore/ranger/graphics"
+	"github.com/wdevore/ranger/rmath"
+)
+
+// TextStyle decorates distance field text. Widths and offsets are in atlas
+// pixels and are limited to half the font's DistanceRange. A zero width or
+// transparent colour disables an effect.
+type TextStyle struct {
+	OutlineColor graphics.Colors
+	OutlineWidth float32
+
+	GlowColor graphics.Colors
+	GlowWidth float32
+
+	ShadowColor graphics.Colors
+	// ShadowOffsetX/Y move the shadow right and down.
+	ShadowOffsetX float32
+	ShadowOffsetY float32
+
+	// Softness blurs all edges, 0 is sharp.
+	Softness float32
+}
+
+// NewTextStyle creates a style without effects.
+func NewTextStyle() *TextStyle {
+	ts := new(TextStyle)
+	return ts
+}
+
+// SDFTextRenderer draws TextMeshes of distance field fonts. It expects a
+// shader with:
+//   attribute 0: vec3 position
+//   attribute 1: vec2 uv
+//   uniform mat4 mvp
+//   uniform vec4 color
+//   uniform sampler2D texture0    distance in alpha, 0.5 = edge
+//   uniform vec4 outlineColor, glowColor, shadowColor
+//   uniform float outlineWidth, glowWidth, softness   in distance units
+//   uniform vec2 shadowOffset     in uv units
+type SDFTextRenderer struct {
+	shader *Shader
+
+	// Style is used when Draw isn't given one.
+	Style TextStyle
+
+	mvp          Uniform
+	color        Uniform
+	texture      Uniform
+	outlineColor Uniform
+	outlineWidth Uniform
+	glowColor    Uniform
+	glowWidth    Uniform
+	shadowColor  Uniform
+	shadowOffset Uniform
+	softness     Uniform
+}
+
+// NewSDFTextRenderer creates a renderer using an SDF text shader. You must
+// call Construct before drawing.
+func NewSDFTextRenderer(shader *Shader) *SDFTextRenderer {
+	sr := new(SDFTextRenderer)
+	sr.shader = shader
+	return sr
+}
+
+// Construct resolves the shader's uniforms. The shader must already be loaded.
+func (sr *SDFTextRenderer) Construct() error {
+	uniforms, err := sr.shader.Uniforms("mvp", "color", "texture0",
+		"outlineColor", "outlineWidth", "glowColor", "glowWidth",
+		"shadowColor", "shadowOffset", "softness")
+	if err != nil {
+		return err
+	}
+
+	sr.mvp, sr.color, sr.texture = uniforms[0], uniforms[1], uniforms[2]
+	sr.outlineColor, sr.outlineWidth = uniforms[3], uniforms[4]
+	sr.glowColor, sr.glowWidth = uniforms[5], uniforms[6]
+	sr.shadowColor, sr.shadowOffset = uniforms[7], uniforms[8]
+	sr.softness = uniforms[9]
+
+	return nil
+}
+
+// Draw renders a TextMesh built from a distance field font's glyphs. A nil
+// style uses the renderer's Style.
+func (sr *SDFTextRenderer) Draw(mesh *TextMesh, font *Font, mvp *rmath.Matrix4, color *graphics.Colors, style *TextStyle) {
+	if style == nil {
+		style = &sr.Style
+	}
+
+	// Pixels to normalized distance.
+	distance := float32(1.0)
+	if font.Atlas.DistanceRange > 0.0 {
+		distance = 1.0 / font.Atlas.DistanceRange
+	}
+
+	sr.shader.Use()
+
+	sr.mvp.SetMatrix4(mvp)
+	sr.texture.SetSampler(0)
+
+	sr.outlineColor.SetColor(&style.OutlineColor)
+	sr.outlineWidth.SetFloat(style.OutlineWidth * distance)
+
+	sr.glowColor.SetColor(&style.GlowColor)
+	sr.glowWidth.SetFloat(style.GlowWidth * distance)
+
+	sr.shadowColor.SetColor(&style.ShadowColor)
+
+	sr.softness.SetFloat(style.Softness * distance)
+
+	mesh.draw(font, sr.color, color, func(page *Texture) {
+		// The offset is in texels of each page.
+		sr.shadowOffset.Set2Components(
+			style.ShadowOffsetX/float32(page.Width),
+			style.ShadowOffsetY/float32(page.Height))
+	})
+}
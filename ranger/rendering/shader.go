@@ -3,10 +3,11 @@ package rendering
 
 import (
 	"fmt"
-	"io/ioutil"
 	"strings"
+	"time"
 
 	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/ranger/graphics"
 )
 
 // Shader represents a shader program
@@ -16,75 +17,180 @@ type Shader struct {
 
 	vertexSrc   string
 	fragmentSrc string
+	sources     []ShaderSource
+
+	// Preprocessor settings
+	defines map[string]string
+	version string
 
 	program uint32 // GLuint
+	// loads counts successful Loads so caches can tell a reload happened.
+	loads int
+
+	// Locations of active uniforms and attributes cached by Load.
+	uniforms   map[string]int32
+	attributes map[string]int32
+	slots      map[string]*uniformSlot
+
+	// Files read by the last Load, including #includes, and the sources
+	// they were read from.
+	files  []string
+	loaded []ShaderSource
 }
 
 // NewShader creates a blank shader. You must call Load before that shader is valid.
-func NewShader(vertexSrc, fragmentSrc string) *Shader {
+// The code is read from the first of the sources that has it. Without
+// sources the AssetsDirectory is tried and then the EmbeddedShaders.
+func NewShader(vertexSrc, fragmentSrc string, sources ...ShaderSource) *Shader {
 	s := new(Shader)
 	s.vertexSrc = vertexSrc
 	s.fragmentSrc = fragmentSrc
+	s.sources = sources
 	return s
 }
 
-// Load reads and compiles shader programs
+// Load reads and compiles shader programs. Loading again replaces the
+// program, and updates Uniforms, only if the new code compiles.
 func (s *Shader) Load() error {
+	sources := s.sources
+	if len(sources) == 0 {
+		sources = []ShaderSource{NewDirSource(AssetsDirectory), EmbeddedShaders}
+	}
 
-	var err error
-	s.vertexCode, s.fragmentCode, err = fetch(s.vertexSrc, s.fragmentSrc)
+	vertex, fragment, err := s.fetch(sources)
 	if err != nil {
 		return err
 	}
 
-	s.program, err = newProgram(s.vertexCode, s.fragmentCode)
+	// Watch the files even if they don't compile so they can be fixed.
+	s.files = append(append([]string{}, vertex.Files...), fragment.Files...)
+	s.loaded = sources
+
+	program, err := newProgram(vertex, fragment)
 	if err != nil {
 		return err
 	}
 
+	s.deleteProgram()
+	graphics.Resources.Created(graphics.ProgramResource, program, s.vertexSrc+", "+s.fragmentSrc)
+
+	s.vertexCode = vertex.Code
+	s.fragmentCode = fragment.Code
+	s.program = program
+	s.loads++
+
+	s.introspect()
+
 	return nil
 }
 
-// Use activates program
-func (s *Shader) Use() {
-	gl.UseProgram(s.program)
+// ModTime returns when the newest of the files read by the last Load was
+// modified. Files of sources that aren't ModTimeSources are ignored.
+func (s *Shader) ModTime() time.Time {
+	newest := time.Time{}
+
+	for _, file := range s.files {
+		for _, source := range s.loaded {
+			mts, ok := source.(ModTimeSource)
+			if !ok {
+				continue
+			}
+
+			// The file came from the first source that has it.
+			t, err := mts.ModTime(file)
+			if err != nil {
+				continue
+			}
+
+			if t.After(newest) {
+				newest = t
+			}
+			break
+		}
+	}
+
+	return newest
 }
 
-func fetch(vertexSrc, fragmentSrc string) (vCode, fCode string, err error) {
-	// Vertex source -----------------------------------------------
-	filePath := fmt.Sprintf("./assets/%s", vertexSrc)
+// Delete deletes the program. Load creates it again.
+func (s *Shader) Delete() {
+	s.deleteProgram()
+}
 
-	var bytes []byte
-	bytes, err = ioutil.ReadFile(filePath)
+func (s *Shader) deleteProgram() {
+	if s.program == 0 {
+		return
+	}
+	gl.DeleteProgram(s.program)
+	graphics.GLState.ProgramDeleted(s.program)
+	graphics.Resources.Deleted(graphics.ProgramResource, s.program)
+	s.program = 0
+}
 
-	if err != nil {
-		return "", "", err
+// Use activates program
+func (s *Shader) Use() {
+	graphics.GLState.UseProgram(s.program)
+}
+
+// Define adds a #define to the code when it's next loaded. 'value' may be
+// empty.
+func (s *Shader) Define(name, value string) {
+	if s.defines == nil {
+		s.defines = map[string]string{}
 	}
+	s.defines[name] = value
+}
 
-	vCode = string(bytes)
+// SetVersion replaces the code's #version, for example, "410 core". Empty
+// keeps the code's and code without one gets the context's version.
+func (s *Shader) SetVersion(version string) {
+	s.version = version
+}
 
-	// Fragment source -----------------------------------------------
-	filePath = fmt.Sprintf("./assets/%s", fragmentSrc)
+// fetch reads and preprocesses both stages.
+func (s *Shader) fetch(sources []ShaderSource) (vertex, fragment *ProcessedShader, err error) {
+	pp := NewPreprocessor(sources)
+	pp.Version = s.version
+	for name, value := range s.defines {
+		pp.Defines[name] = value
+	}
 
-	bytes, err = ioutil.ReadFile(filePath)
+	vertex, err = pp.Process(s.vertexSrc)
+	if err != nil {
+		return nil, nil, err
+	}
 
+	fragment, err = pp.Process(s.fragmentSrc)
 	if err != nil {
-		return "", "", err
+		return nil, nil, err
 	}
 
-	fCode = string(bytes)
+	if !vertex.hasVersion || !fragment.hasVersion {
+		version := contextGLSLVersion()
+		vertex.ensureVersion(version)
+		fragment.ensureVersion(version)
+	}
 
 	return
 }
 
-func newProgram(vertexShaderSource, fragmentShaderSource string) (uint32, error) {
-	vertexShader, err := compile(vertexShaderSource, gl.VERTEX_SHADER)
+// contextGLSLVersion returns the #version matching the current context.
+func contextGLSLVersion() string {
+	var major, minor int32
+	gl.GetIntegerv(gl.MAJOR_VERSION, &major)
+	gl.GetIntegerv(gl.MINOR_VERSION, &minor)
+	return GLSLVersion(int(major), int(minor))
+}
+
+func newProgram(vertex, fragment *ProcessedShader) (uint32, error) {
+	vertexShader, err := compile(vertex, gl.VERTEX_SHADER)
 	if err != nil {
 		return 0, err
 	}
 
-	fragmentShader, err := compile(fragmentShaderSource, gl.FRAGMENT_SHADER)
+	fragmentShader, err := compile(fragment, gl.FRAGMENT_SHADER)
 	if err != nil {
+		gl.DeleteShader(vertexShader)
 		return 0, err
 	}
 
@@ -94,6 +200,10 @@ func newProgram(vertexShaderSource, fragmentShaderSource string) (uint32, error)
 	gl.AttachShader(program, fragmentShader)
 	gl.LinkProgram(program)
 
+	// The program keeps them until it's deleted.
+	gl.DeleteShader(vertexShader)
+	gl.DeleteShader(fragmentShader)
+
 	var status int32
 	gl.GetProgramiv(program, gl.LINK_STATUS, &status)
 	if status == gl.FALSE {
@@ -103,20 +213,19 @@ func newProgram(vertexShaderSource, fragmentShaderSource string) (uint32, error)
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetProgramInfoLog(program, logLength, nil, gl.Str(log))
 
+		gl.DeleteProgram(program)
+
 		return 0, fmt.Errorf("failed to link program: %v", log)
 	}
 
-	gl.DeleteShader(vertexShader)
-	gl.DeleteShader(fragmentShader)
-
 	return program, nil
 
 }
 
-func compile(source string, shaderType uint32) (uint32, error) {
+func compile(source *ProcessedShader, shaderType uint32) (uint32, error) {
 	shader := gl.CreateShader(shaderType)
 
-	csources, free := gl.Strs(source)
+	csources, free := gl.Strs(source.Code)
 	gl.ShaderSource(shader, 1, csources, nil)
 	free()
 	gl.CompileShader(shader)
@@ -130,7 +239,9 @@ func compile(source string, shaderType uint32) (uint32, error) {
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetShaderInfoLog(shader, logLength, nil, gl.Str(log))
 
-		return 0, fmt.Errorf("failed to compile %v: %v", source, log)
+		gl.DeleteShader(shader)
+
+		return 0, fmt.Errorf("failed to compile %s:\n%s", source.Name, source.MapLog(strings.TrimRight(log, "\x00")))
 	}
 
 	return shader, nil
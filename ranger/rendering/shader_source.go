@@ -0,0 +1,138 @@
+// Package rendering defines where shader code comes from.
+package rendering
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"time"
+)
+
+// ShaderSource provides shader code by name, for example, "sprite.vert".
+type ShaderSource interface {
+	ReadShader(name string) (string, error)
+}
+
+// ModTimeSource is a ShaderSource that knows when its files change, which
+// lets a ShaderWatcher reload them.
+type ModTimeSource interface {
+	ModTime(name string) (time.Time, error)
+}
+
+//go:embed shaders/*.vert shaders/*.frag
+var embeddedShaders embed.FS
+
+// EmbeddedShaders are the built-in shaders compiled into the binary:
+//   sprite.vert/frag             SpriteRenderer
+//   text.vert/frag               TextRenderer
+//   sdf.vert/frag                SDFTextRenderer
+//   vector.vert/frag             VectorShapeRenderer
+//   vector_color.vert/frag       VectorShapeRenderer of a VectorColorAtlas,
+//                                VectorBatchRenderer
+//   vector_instanced.vert/frag   InstancedShapeRenderer
+var EmbeddedShaders ShaderSource = NewFSSource(embeddedShaders, "shaders")
+
+// AssetsDirectory is read before the built-in shaders by Shaders that
+// aren't given sources. It is relative to the working directory.
+var AssetsDirectory = "./assets"
+
+// FSSource reads shaders from a directory of an fs.FS such as an embed.FS,
+// os.DirFS or fstest.MapFS.
+type FSSource struct {
+	fsys fs.FS
+	dir  string
+}
+
+// NewFSSource creates a source reading from 'dir' of 'fsys'. Use "." for
+// the root.
+func NewFSSource(fsys fs.FS, dir string) *FSSource {
+	fss := new(FSSource)
+	fss.fsys = fsys
+	fss.dir = dir
+	return fss
+}
+
+// NewDirSource creates a source reading from a directory of the OS.
+func NewDirSource(dir string) *FSSource {
+	return NewFSSource(os.DirFS(dir), ".")
+}
+
+// ReadShader reads the named file.
+func (fss *FSSource) ReadShader(name string) (string, error) {
+	bytes, err := fs.ReadFile(fss.fsys, path.Join(fss.dir, name))
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// ModTime returns when the named file was last modified. Embedded files
+// are never modified.
+func (fss *FSSource) ModTime(name string) (time.Time, error) {
+	info, err := fs.Stat(fss.fsys, path.Join(fss.dir, name))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+// MapSource is an in-memory source of shader code keyed by name.
+type MapSource map[string]string
+
+// ReadShader returns the named code.
+func (ms MapSource) ReadShader(name string) (string, error) {
+	code, ok := ms[name]
+	if !ok {
+		return "", fmt.Errorf("no shader named '%s'", name)
+	}
+	return code, nil
+}
+
+// readShader returns code from the first source that has it.
+func readShader(name string, sources []ShaderSource) (string, error) {
+	var errs []error
+
+	for _, source := range sources {
+		code, err := source.ReadShader(name)
+		if err == nil {
+			return code, nil
+		}
+		errs = append(errs, err)
+	}
+
+	return "", fmt.Errorf("unable to read shader '%s': %v", name, errs)
+}
+
+// NewSpriteShader creates the built-in shader for SpriteRenderer.
+func NewSpriteShader() *Shader {
+	return NewShader("sprite.vert", "sprite.frag", EmbeddedShaders)
+}
+
+// NewTextShader creates the built-in shader for TextRenderer.
+func NewTextShader() *Shader {
+	return NewShader("text.vert", "text.frag", EmbeddedShaders)
+}
+
+// NewSDFTextShader creates the built-in shader for SDFTextRenderer.
+func NewSDFTextShader() *Shader {
+	return NewShader("sdf.vert", "sdf.frag", EmbeddedShaders)
+}
+
+// NewVectorShader creates the built-in shader for VectorShapeRenderer.
+func NewVectorShader() *Shader {
+	return NewShader("vector.vert", "vector.frag", EmbeddedShaders)
+}
+
+// NewVectorColorShader creates the built-in shader for VectorShapeRenderer
+// drawing VectorColorAtlas shapes. The color uniform tints the vertices.
+func NewVectorColorShader() *Shader {
+	return NewShader("vector_color.vert", "vector_color.frag", EmbeddedShaders)
+}
+
+// NewVectorInstancedShader creates the built-in shader for
+// InstancedShapeRenderer.
+func NewVectorInstancedShader() *Shader {
+	return NewShader("vector_instanced.vert", "vector_instanced.frag", EmbeddedShaders)
+}
@@ -0,0 +1,99 @@
+package rendering
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func Test_EmbeddedShaders(t *testing.T) {
+	names := []string{"sprite", "text", "sdf", "vector", "vector_color"}
+
+	for _, name := range names {
+		for _, ext := range []string{".vert", ".frag"} {
+			code, err := EmbeddedShaders.ReadShader(name + ext)
+			if err != nil {
+				t.Errorf("Expected embedded %s%s, got: %s", name, ext, err.Error())
+				continue
+			}
+			if !strings.HasPrefix(code, "#version") {
+				t.Errorf("Expected %s%s to start with #version", name, ext)
+			}
+		}
+	}
+}
+
+func Test_ShaderSource_Order(t *testing.T) {
+	fsys := fstest.MapFS{
+		"game/sprite.frag": &fstest.MapFile{Data: []byte("custom")},
+	}
+
+	sources := []ShaderSource{
+		MapSource{"text.frag": "memory"},
+		NewFSSource(fsys, "game"),
+		EmbeddedShaders,
+	}
+
+	expected := map[string]string{
+		"text.frag":   "memory",
+		"sprite.frag": "custom",
+	}
+
+	for name, e := range expected {
+		code, err := readShader(name, sources)
+		if err != nil || code != e {
+			t.Errorf("Expected '%s' for %s, got: '%s' %v", e, name, code, err)
+		}
+	}
+
+	code, err := readShader("vector.vert", sources)
+	if err != nil || !strings.Contains(code, "mvp") {
+		t.Errorf("Expected the embedded vector.vert, got: %v", err)
+	}
+
+	if _, err = readShader("missing.frag", sources); err == nil {
+		t.Errorf("Expected an error for missing.frag")
+	}
+}
+
+func Test_Shader_ModTime(t *testing.T) {
+	old := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := old.Add(time.Hour)
+
+	fsys := fstest.MapFS{
+		"fx.frag":     &fstest.MapFile{Data: []byte("#version 330 core\n#include \"common.glsl\"\n"), ModTime: old},
+		"common.glsl": &fstest.MapFile{Data: []byte("float x;\n"), ModTime: old},
+	}
+
+	s := NewShader("sprite.vert", "fx.frag", NewFSSource(fsys, "."), EmbeddedShaders)
+
+	vertex, fragment, err := s.fetch(s.sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fragment.Files) != 2 || fragment.Files[1] != "common.glsl" {
+		t.Fatalf("Expected fx.frag to read common.glsl, got: %v", fragment.Files)
+	}
+
+	s.files = append(vertex.Files, fragment.Files...)
+	s.loaded = s.sources
+
+	// sprite.vert is embedded which never changes.
+	if !s.ModTime().Equal(old) {
+		t.Errorf("Expected %v, got: %v", old, s.ModTime())
+	}
+
+	sw := NewShaderWatcher()
+	sw.Watch(s)
+
+	if sw.Check() != 0 {
+		t.Errorf("Expected nothing to reload")
+	}
+
+	fsys["common.glsl"].ModTime = newer
+	if !s.ModTime().Equal(newer) {
+		t.Errorf("Expected an include's change to be seen, got: %v", s.ModTime())
+	}
+}
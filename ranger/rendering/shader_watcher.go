@@ -0,0 +1,119 @@
+// Package rendering defines shader hot reloading.
+package rendering
+
+import (
+	"time"
+)
+
+// ShaderWatcher reloads Shaders when their files change while the game
+// runs. It polls the files' modification times so it's meant for
+// development. Only files of ModTimeSources, for example, NewDirSource,
+// are watched.
+type ShaderWatcher struct {
+	// Interval between polls in seconds. Default is 0.5
+	Interval float32
+
+	// OnError is called when a changed Shader fails to compile. The Shader
+	// keeps its previous program. The default prints the error.
+	OnError func(shader *Shader, err error)
+	// OnReload is optional and called after a Shader is reloaded.
+	OnReload func(shader *Shader)
+
+	shaders []*watchedShader
+	elapsed float32
+}
+
+type watchedShader struct {
+	shader *Shader
+	stamp  time.Time
+	// err is the failure of the last reload, until one succeeds.
+	err error
+}
+
+// NewShaderWatcher creates a watcher that prints compile errors.
+func NewShaderWatcher() *ShaderWatcher {
+	sw := new(ShaderWatcher)
+	sw.Interval = 0.5
+	sw.OnError = func(shader *Shader, err error) {
+		println(err.Error())
+	}
+	return sw
+}
+
+// Watch reloads the Shader when its files change. It must have been loaded.
+func (sw *ShaderWatcher) Watch(shader *Shader) {
+	for _, ws := range sw.shaders {
+		if ws.shader == shader {
+			return
+		}
+	}
+
+	sw.shaders = append(sw.shaders, &watchedShader{shader: shader, stamp: shader.ModTime()})
+}
+
+// Unwatch stops watching the Shader.
+func (sw *ShaderWatcher) Unwatch(shader *Shader) {
+	for i, ws := range sw.shaders {
+		if ws.shader == shader {
+			sw.shaders = append(sw.shaders[:i], sw.shaders[i+1:]...)
+			return
+		}
+	}
+}
+
+// LastError returns the error of a watched Shader whose last reload
+// failed, nil once every Shader has reloaded successfully. Games can show
+// it on screen.
+func (sw *ShaderWatcher) LastError() error {
+	for _, ws := range sw.shaders {
+		if ws.err != nil {
+			return ws.err
+		}
+	}
+	return nil
+}
+
+// Update polls once per Interval. It must be called on the thread that
+// owns the GL context.
+func (sw *ShaderWatcher) Update(dt float32) {
+	sw.elapsed += dt
+	if sw.elapsed < sw.Interval {
+		return
+	}
+	sw.elapsed = 0.0
+
+	sw.Check()
+}
+
+// Check reloads the Shaders whose files changed and returns how many were
+// reloaded successfully.
+func (sw *ShaderWatcher) Check() int {
+	reloaded := 0
+
+	for _, ws := range sw.shaders {
+		stamp := ws.shader.ModTime()
+		if !stamp.After(ws.stamp) {
+			continue
+		}
+
+		// Either way the change has been seen.
+		ws.stamp = stamp
+
+		err := ws.shader.Load()
+		ws.err = err
+
+		if err != nil {
+			if sw.OnError != nil {
+				sw.OnError(ws.shader, err)
+			}
+			continue
+		}
+
+		reloaded++
+		if sw.OnReload != nil {
+			sw.OnReload(ws.shader)
+		}
+	}
+
+	return reloaded
+}
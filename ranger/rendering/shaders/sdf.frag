@@ -0,0 +1,64 @@
+#version 330 core
+in vec2 uv;
+
+uniform sampler2D texture0;
+uniform vec4 color;
+
+uniform vec4 outlineColor;
+uniform float outlineWidth;
+uniform vec4 glowColor;
+uniform float glowWidth;
+uniform vec4 shadowColor;
+uniform vec2 shadowOffset;
+uniform float softness;
+
+out vec4 fragColor;
+
+// coverage converts a distance to alpha around 'edge'. 0.5 is the glyph's edge.
+float coverage(float distance, float edge, float width)
+{
+    return smoothstep(edge - width, edge + width, distance);
+}
+
+// over composites a premultiplied layer over another.
+vec4 over(vec4 top, vec4 bottom)
+{
+    return top + bottom * (1.0 - top.a);
+}
+
+void main()
+{
+    // Glyph pages store the distance in the alpha channel.
+    float d = texture(texture0, uv).a;
+
+    // Anti-alias over about one screen pixel regardless of scale.
+    float aa = max(fwidth(d) * 0.5, 0.0001) + softness;
+
+    vec4 result = vec4(0.0);
+
+    if (shadowColor.a > 0.0) {
+        float sd = texture(texture0, uv - shadowOffset).a;
+        float a = coverage(sd, 0.5 - outlineWidth, aa) * shadowColor.a;
+        result = over(vec4(shadowColor.rgb * a, a), result);
+    }
+
+    if (glowWidth > 0.0 && glowColor.a > 0.0) {
+        float a = smoothstep(0.5 - outlineWidth - glowWidth, 0.5 - outlineWidth, d) * glowColor.a;
+        result = over(vec4(glowColor.rgb * a, a), result);
+    }
+
+    if (outlineWidth > 0.0 && outlineColor.a > 0.0) {
+        float a = coverage(d, 0.5 - outlineWidth, aa) * outlineColor.a;
+        result = over(vec4(outlineColor.rgb * a, a), result);
+    }
+
+    float a = coverage(d, 0.5, aa) * color.a;
+    result = over(vec4(color.rgb * a, a), result);
+
+    // Back to straight alpha for the SRC_ALPHA blend.
+    if (result.a > 0.0) {
+        result.rgb /= result.a;
+    }
+
+    fragColor = result;
+}
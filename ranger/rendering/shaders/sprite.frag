@@ -0,0 +1,12 @@
+#version 330 core
+in vec2 uv;
+
+uniform sampler2D texture0;
+uniform vec4 tint;
+
+out vec4 fragColor;
+
+void main()
+{
+    fragColor = texture(texture0, uv) * tint;
+}
@@ -0,0 +1,15 @@
+#version 330 core
+layout (location = 0) in vec3 aPos;
+layout (location = 1) in vec2 aUV;
+
+uniform mat4 mvp;
+// Offset (xy) and scale (zw) selecting a source rectangle of the texture.
+uniform vec4 uvRect;
+
+out vec2 uv;
+
+void main()
+{
+    gl_Position = mvp * vec4(aPos, 1.0);
+    uv = uvRect.xy + aUV * uvRect.zw;
+}
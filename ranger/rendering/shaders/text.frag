@@ -0,0 +1,13 @@
+#version 330 core
+in vec2 uv;
+
+uniform sampler2D texture0;
+uniform vec4 color;
+
+out vec4 fragColor;
+
+void main()
+{
+    // Glyph pages store coverage in the alpha channel.
+    fragColor = vec4(color.rgb, color.a * texture(texture0, uv).a);
+}
@@ -0,0 +1,13 @@
+#version 330 core
+layout (location = 0) in vec3 aPos;
+layout (location = 2) in vec4 aColor;
+
+uniform mat4 mvp;
+
+out vec4 vertexColor;
+
+void main()
+{
+    gl_Position = mvp * vec4(aPos, 1.0);
+    vertexColor = aColor;
+}
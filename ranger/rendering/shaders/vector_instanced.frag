@@ -0,0 +1,12 @@
+#version 330 core
+in vec4 vertexColor;
+
+// Tints every instance's colour, White leaves them unchanged.
+uniform vec4 color;
+
+out vec4 fragColor;
+
+void main()
+{
+    fragColor = vertexColor * color;
+}
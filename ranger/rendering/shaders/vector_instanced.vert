@@ -0,0 +1,20 @@
+#version 330 core
+layout (location = 0) in vec3 aPos;
+
+// Per instance: the rows of a 2D affine transform and a colour.
+layout (location = 4) in vec3 iRow0;
+layout (location = 5) in vec3 iRow1;
+layout (location = 6) in vec4 iColor;
+
+uniform mat4 mvp;
+
+out vec4 vertexColor;
+
+void main()
+{
+    vec3 p = vec3(aPos.xy, 1.0);
+    vec2 local = vec2(dot(iRow0, p), dot(iRow1, p));
+
+    gl_Position = mvp * vec4(local, aPos.z, 1.0);
+    vertexColor = iColor;
+}
@@ -0,0 +1,279 @@
+// Package rendering defines a software render backend.
+package rendering
+
+import (
+	"image"
+	"math"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/ranger/graphics"
+	"github.com/wdevore/ranger/rmath"
+)
+
+// SoftwareBackend rasterises RenderCommands into an image without GL, for
+// example, to render scenes headlessly and compare them with golden
+// images. It reads the same draw data the GL backend uploads: the object's
+// vertices and indices, the shape's primitive mode, the command's
+// transform, colour and blend mode. Vertex colours, if any, are multiplied
+// with the command's colour as the vector_color shader does.
+//
+// The Target holds what a GL framebuffer would, straight (not
+// premultiplied) channels blended exactly as GL blends them, so it can be
+// saved and compared with PNGs losslessly.
+type SoftwareBackend struct {
+	Target *image.NRGBA
+
+	// DrawCalls made by the last Execute.
+	DrawCalls int
+
+	// Scratch
+	point    rmath.Vector3
+	indices  []uint32
+	vertices []softVertex
+}
+
+// softVertex is a transformed vertex in pixel coordinates (y down).
+type softVertex struct {
+	x, y       float32
+	r, g, b, a float32
+}
+
+// NewSoftwareBackend creates a backend with a transparent width x height
+// Target.
+func NewSoftwareBackend(width, height int) *SoftwareBackend {
+	sb := new(SoftwareBackend)
+	sb.Target = image.NewNRGBA(image.Rect(0, 0, width, height))
+	return sb
+}
+
+// Clear fills the Target with a colour.
+func (sb *SoftwareBackend) Clear(color *graphics.Colors) {
+	r, g, b, a := toByte(color.R), toByte(color.G), toByte(color.B), toByte(color.A)
+	pix := sb.Target.Pix
+	for i := 0; i < len(pix); i += 4 {
+		pix[i], pix[i+1], pix[i+2], pix[i+3] = r, g, b, a
+	}
+}
+
+// Execute rasterises the commands in order.
+func (sb *SoftwareBackend) Execute(commands []RenderCommand) {
+	sb.DrawCalls = 0
+
+	for i := range commands {
+		sb.draw(&commands[i])
+		sb.DrawCalls++
+	}
+}
+
+func (sb *SoftwareBackend) draw(c *RenderCommand) {
+	m := c.Object.vao.mesh
+
+	first := c.Shape.Offset() / indexSize
+	shapeIndices := m.Indices[first : first+int(c.Shape.Count)]
+	sb.indices = expandIndices(c.Shape.PrimitiveMode, shapeIndices, sb.indices[:0])
+
+	sb.transform(m, c)
+
+	switch primitiveClass(c.Shape.PrimitiveMode) {
+	case gl.TRIANGLES:
+		for i := 0; i+2 < len(sb.indices); i += 3 {
+			sb.triangle(&sb.vertices[sb.indices[i]], &sb.vertices[sb.indices[i+1]], &sb.vertices[sb.indices[i+2]], c.Blend)
+		}
+	case gl.LINES:
+		for i := 0; i+1 < len(sb.indices); i += 2 {
+			sb.line(&sb.vertices[sb.indices[i]], &sb.vertices[sb.indices[i+1]], c.Blend)
+		}
+	default:
+		for _, i := range sb.indices {
+			v := &sb.vertices[i]
+			sb.plot(int(math.Floor(float64(v.x))), int(math.Floor(float64(v.y))), v.r, v.g, v.b, v.a, c.Blend)
+		}
+	}
+}
+
+// transform converts the mesh's vertices to pixel coordinates and colours.
+func (sb *SoftwareBackend) transform(m *Mesh, c *RenderCommand) {
+	format := m.VertexFormat()
+	vertexCount := m.VertexCount()
+
+	position := format.attributeIndex(PositionAttribute.Name)
+	if position < 0 {
+		position = 0
+	}
+	components := format.Attributes[position].Components
+	colorAttr := format.attributeIndex(ColorAttribute.Name)
+
+	width := float32(sb.Target.Rect.Dx())
+	height := float32(sb.Target.Rect.Dy())
+
+	sb.vertices = sb.vertices[:0]
+	for v := 0; v < vertexCount; v++ {
+		p := componentIndex(format, position, v, vertexCount)
+		sb.point.Set3Components(m.Vertices[p], m.Vertices[p+1], 0.0)
+		if components > 2 {
+			sb.point.Z = m.Vertices[p+2]
+		}
+		sb.point.Mul(&c.Transform)
+
+		sv := softVertex{
+			// NDC to pixels, GL's y is up.
+			x: (sb.point.X + 1.0) * 0.5 * width,
+			y: (1.0 - sb.point.Y) * 0.5 * height,
+			r: c.Color.R, g: c.Color.G, b: c.Color.B, a: c.Color.A,
+		}
+
+		if colorAttr >= 0 {
+			ci := componentIndex(format, colorAttr, v, vertexCount)
+			sv.r *= m.Vertices[ci]
+			sv.g *= m.Vertices[ci+1]
+			sv.b *= m.Vertices[ci+2]
+			sv.a *= m.Vertices[ci+3]
+		}
+
+		sb.vertices = append(sb.vertices, sv)
+	}
+}
+
+// edge is positive when 'p' is to the right of a->b (y down).
+func edge(a, b *softVertex, px, py float32) float32 {
+	return (b.x-a.x)*(py-a.y) - (b.y-a.y)*(px-a.x)
+}
+
+// isTopLeft implements GL's fill convention so pixels on an edge shared
+// by two triangles are drawn once.
+func isTopLeft(a, b *softVertex) bool {
+	dx, dy := b.x-a.x, b.y-a.y
+	return dy < 0 || (dy == 0 && dx > 0)
+}
+
+// triangle fills the pixels whose centers are inside, interpolating the
+// vertex colours.
+func (sb *SoftwareBackend) triangle(v0, v1, v2 *softVertex, blend BlendMode) {
+	area := edge(v0, v1, v2.x, v2.y)
+	if area == 0 {
+		return
+	}
+	if area < 0 {
+		v1, v2 = v2, v1
+		area = -area
+	}
+
+	bounds := sb.Target.Rect
+	minX := maxInt(bounds.Min.X, int(math.Floor(float64(min3(v0.x, v1.x, v2.x)))))
+	maxX := minInt(bounds.Max.X-1, int(math.Ceil(float64(max3(v0.x, v1.x, v2.x)))))
+	minY := maxInt(bounds.Min.Y, int(math.Floor(float64(min3(v0.y, v1.y, v2.y)))))
+	maxY := minInt(bounds.Max.Y-1, int(math.Ceil(float64(max3(v0.y, v1.y, v2.y)))))
+
+	tl0, tl1, tl2 := isTopLeft(v1, v2), isTopLeft(v2, v0), isTopLeft(v0, v1)
+
+	for y := minY; y <= maxY; y++ {
+		py := float32(y) + 0.5
+		for x := minX; x <= maxX; x++ {
+			px := float32(x) + 0.5
+
+			w0 := edge(v1, v2, px, py)
+			w1 := edge(v2, v0, px, py)
+			w2 := edge(v0, v1, px, py)
+
+			if !inside(w0, tl0) || !inside(w1, tl1) || !inside(w2, tl2) {
+				continue
+			}
+
+			w0, w1, w2 = w0/area, w1/area, w2/area
+			sb.plot(x, y,
+				w0*v0.r+w1*v1.r+w2*v2.r,
+				w0*v0.g+w1*v1.g+w2*v2.g,
+				w0*v0.b+w1*v1.b+w2*v2.b,
+				w0*v0.a+w1*v1.a+w2*v2.a,
+				blend)
+		}
+	}
+}
+
+func inside(w float32, topLeft bool) bool {
+	return w > 0 || (w == 0 && topLeft)
+}
+
+// line draws a one pixel wide line, without its last pixel so connected
+// lines don't draw their shared pixels twice.
+func (sb *SoftwareBackend) line(v0, v1 *softVertex, blend BlendMode) {
+	dx, dy := v1.x-v0.x, v1.y-v0.y
+
+	steps := int(math.Round(math.Max(math.Abs(float64(dx)), math.Abs(float64(dy)))))
+	if steps == 0 {
+		return
+	}
+
+	for i := 0; i < steps; i++ {
+		t := float32(i) / float32(steps)
+		x := int(math.Floor(float64(v0.x + dx*t)))
+		y := int(math.Floor(float64(v0.y + dy*t)))
+		sb.plot(x, y,
+			v0.r+(v1.r-v0.r)*t,
+			v0.g+(v1.g-v0.g)*t,
+			v0.b+(v1.b-v0.b)*t,
+			v0.a+(v1.a-v0.a)*t,
+			blend)
+	}
+}
+
+// plot blends a fragment into the Target as GL's blend functions do.
+func (sb *SoftwareBackend) plot(x, y int, r, g, b, a float32, blend BlendMode) {
+	if !(image.Point{X: x, Y: y}).In(sb.Target.Rect) {
+		return
+	}
+
+	i := sb.Target.PixOffset(x, y)
+	pix := sb.Target.Pix[i : i+4 : i+4]
+	src := [4]float32{r, g, b, a}
+
+	for c := 0; c < 4; c++ {
+		dst := float32(pix[c]) / 255.0
+
+		var out float32
+		switch blend {
+		case BlendNone:
+			out = src[c]
+		case BlendAdditive:
+			out = src[c]*a + dst
+		case BlendPremultiplied:
+			out = src[c] + dst*(1.0-a)
+		default:
+			out = src[c]*a + dst*(1.0-a)
+		}
+
+		pix[c] = toByte(out)
+	}
+}
+
+func toByte(v float32) uint8 {
+	if v <= 0.0 {
+		return 0
+	}
+	if v >= 1.0 {
+		return 255
+	}
+	return uint8(v*255.0 + 0.5)
+}
+
+func min3(a, b, c float32) float32 {
+	return float32(math.Min(float64(a), math.Min(float64(b), float64(c))))
+}
+
+func max3(a, b, c float32) float32 {
+	return float32(math.Max(float64(a), math.Max(float64(b), float64(c))))
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,152 @@
+package rendering
+
+import (
+	"flag"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/ranger/graphics"
+	"github.com/wdevore/ranger/rmath"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden images in testdata")
+
+// softwareTransform places a unit shape, scaled to 'size' pixels, at x,y
+// of a 64x64 target with a y up projection.
+func softwareTransform(x, y, size float32) *rmath.Matrix4 {
+	var projection, model, mvp rmath.Matrix4
+	projection.SetToOrtho(0.0, 64.0, 0.0, 64.0, -1.0, 1.0)
+	model.SetTranslate3Comp(x, y, 0.0)
+	model.ScaleBy(rmath.NewVector3With3Components(size, size, 1.0))
+	rmath.Multiply(&projection, &model, &mvp)
+	return &mvp
+}
+
+func Test_Software_FilledSquare(t *testing.T) {
+	vo, _, filled := batchSquare()
+
+	sb := NewSoftwareBackend(64, 64)
+	rq := NewRenderQueue(NewVectorShader(), sb)
+
+	// Half transparent so pixels blended twice would show.
+	red := graphics.NewColors().Set(1.0, 0.0, 0.0, 0.5)
+	rq.Draw(vo, filled, softwareTransform(32.0, 32.0, 32.0), red)
+	rq.Flush()
+
+	covered := 0
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			c := sb.Target.NRGBAAt(x, y)
+			if c.A == 0 {
+				continue
+			}
+			covered++
+			if c.R != 128 || c.A != 64 {
+				t.Fatalf("Expected every pixel blended once, got: %v at %d,%d", c, x, y)
+			}
+			if x < 16 || x >= 48 || y < 16 || y >= 48 {
+				t.Errorf("Expected pixel %d,%d to be outside the square", x, y)
+			}
+		}
+	}
+
+	if covered != 32*32 {
+		t.Errorf("Expected %d pixels, got: %d", 32*32, covered)
+	}
+}
+
+func Test_Software_Outline(t *testing.T) {
+	vo, outline, _ := batchSquare()
+
+	sb := NewSoftwareBackend(64, 64)
+	rq := NewRenderQueue(NewVectorShader(), sb)
+	rq.Blend = BlendNone
+
+	rq.Draw(vo, outline, softwareTransform(32.0, 32.0, 32.0), graphics.White)
+	rq.Flush()
+
+	// A loop of 4 lines each 32 pixels long without their last pixel.
+	covered := 0
+	for i := 3; i < len(sb.Target.Pix); i += 4 {
+		if sb.Target.Pix[i] != 0 {
+			covered++
+		}
+	}
+	if covered != 4*32 {
+		t.Errorf("Expected %d pixels, got: %d", 4*32, covered)
+	}
+}
+
+func Test_Software_Golden(t *testing.T) {
+	vo, outline, filled := batchSquare()
+
+	gradient := NewVectorObject()
+	gradient.ConstructColored()
+	ca := gradient.ColorAtlas
+	triangle := NewVectorShape()
+	triangle.PrimitiveMode = gl.TRIANGLES
+	triangle.SetOffset(ca.Begin())
+	ca.Add2Component(-0.5, -0.5, graphics.NewColors().Set(1.0, 0.0, 0.0, 1.0))
+	ca.Add2Component(0.5, -0.5, graphics.NewColors().Set(0.0, 1.0, 0.0, 1.0))
+	ca.Add2Component(0.0, 0.5, graphics.NewColors().Set(0.0, 0.0, 1.0, 1.0))
+	triangle.Count = int32(ca.End())
+
+	sb := NewSoftwareBackend(64, 64)
+	sb.Clear(graphics.NewColors().Set(0.1, 0.1, 0.2, 1.0))
+
+	rq := NewRenderQueue(NewVectorShader(), sb)
+
+	rq.Layer = 1
+	rq.Draw(vo, filled, softwareTransform(24.0, 40.0, 30.0), graphics.NewColors().Set(1.0, 0.5, 0.0, 0.75))
+	rq.Blend = BlendAdditive
+	rq.Draw(vo, outline, softwareTransform(40.0, 24.0, 30.0), graphics.NewColors().Set(0.0, 1.0, 1.0, 1.0))
+
+	// Drawn first as it is on a lower layer.
+	rq.Layer = 0
+	rq.Blend = BlendAlpha
+	rq.Shader = NewVectorColorShader()
+	rq.Draw(gradient, triangle, softwareTransform(32.0, 32.0, 56.0), graphics.White)
+
+	rq.Flush()
+
+	path := filepath.Join("testdata", "software_scene.png")
+
+	if *updateGolden {
+		err := SavePNG(path, sb.Target)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	golden, err := LoadPNG(path)
+	if err != nil {
+		t.Fatalf("Expected golden image (run with -update to create it), got: %v", err)
+	}
+
+	different, err := CompareImages(sb.Target, golden, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if different > 0 {
+		t.Errorf("Expected the scene to match %s, got: %d different pixels", path, different)
+	}
+}
+
+func Test_CompareImages(t *testing.T) {
+	a := NewSoftwareBackend(4, 4)
+	b := NewSoftwareBackend(4, 4)
+
+	b.Target.Pix[0] = 3
+	b.Target.Pix[5] = 1
+
+	different, _ := CompareImages(a.Target, b.Target, 2)
+	if different != 1 {
+		t.Errorf("Expected 1 different pixel, got: %d", different)
+	}
+
+	_, err := CompareImages(a.Target, NewSoftwareBackend(2, 2).Target, 0)
+	if err == nil {
+		t.Errorf("Expected an error comparing different sizes")
+	}
+}
@@ -0,0 +1,129 @@
+// Package rendering defines a textured quad renderer.
+package rendering
+
+import (
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/ranger/graphics"
+	"github.com/wdevore/ranger/rmath"
+)
+
+// SpriteRenderer draws textured unit quads. It expects a shader with:
+//   attribute 0: vec3 position
+//   attribute 1: vec2 uv
+//   uniform mat4 mvp     the model-view-projection matrix
+//   uniform vec4 uvRect  offset (xy) and scale (zw) applied to the uv
+//   uniform vec4 tint    multiplied with the texel
+//   uniform sampler2D texture0
+type SpriteRenderer struct {
+	shader *Shader
+	mesh   Mesh
+
+	genBound bool
+	vaoID    uint32
+
+	mvp     Uniform
+	uvRect  Uniform
+	tint    Uniform
+	texture Uniform
+}
+
+// NewSpriteRenderer creates a renderer using a sprite shader. You must
+// call Construct before drawing.
+func NewSpriteRenderer(shader *Shader) *SpriteRenderer {
+	sr := new(SpriteRenderer)
+	sr.shader = shader
+	return sr
+}
+
+// Construct builds the quad and resolves the shader's uniforms. The shader
+// must already be loaded.
+func (sr *SpriteRenderer) Construct() error {
+	// A unit quad centered on the origin. The image's first row is at V = 0
+	// so the top of the quad maps to V = 0.
+	sr.mesh.Vertices = []float32{
+		-0.5, -0.5, 0.0, 0.0, 1.0,
+		0.5, -0.5, 0.0, 1.0, 1.0,
+		0.5, 0.5, 0.0, 1.0, 0.0,
+		-0.5, 0.5, 0.0, 0.0, 0.0,
+	}
+	sr.mesh.Indices = []uint32{0, 1, 2, 0, 2, 3}
+	sr.mesh.Format = PositionUVFormat
+
+	if !sr.genBound {
+		sr.vaoID = genVertexArray()
+		sr.genBound = true
+	}
+	sr.mesh.GenBuffers()
+
+	sr.mesh.bindLayout(sr.vaoID)
+
+	uniforms, err := sr.shader.Uniforms("mvp", "uvRect", "tint")
+	if err != nil {
+		return err
+	}
+	sr.mvp, sr.uvRect, sr.tint = uniforms[0], uniforms[1], uniforms[2]
+
+	// Optional, samplers default to unit 0.
+	sr.texture, _ = sr.shader.Uniform("texture0")
+
+	return nil
+}
+
+// Delete deletes the quad's VAO and buffers. The shader isn't deleted as
+// it may be shared. Construct creates them again.
+func (sr *SpriteRenderer) Delete() {
+	if sr.genBound {
+		deleteVertexArray(sr.vaoID)
+		sr.vaoID = 0
+		sr.genBound = false
+	}
+	sr.mesh.Delete()
+}
+
+// Draw renders the texture's source rectangle (in texels, nil = whole texture)
+// as a quad. 'mvp' maps the unit quad to clip space and should already include
+// the source dimensions.
+func (sr *SpriteRenderer) Draw(texture *Texture, mvp *rmath.Matrix4, tint *graphics.Colors, source *rmath.Rectangle, flipX, flipY bool) {
+	sr.shader.Use()
+
+	texture.Use(0)
+	sr.texture.SetSampler(0)
+
+	sr.mvp.SetMatrix4(mvp)
+
+	u, v, su, sv := UVRect(texture, source, flipX, flipY)
+	sr.uvRect.Set4Components(u, v, su, sv)
+
+	sr.tint.SetColor(tint)
+
+	graphics.GLState.BindVertexArray(sr.vaoID)
+	gl.DrawElements(gl.TRIANGLES, int32(len(sr.mesh.Indices)), gl.UNSIGNED_INT, gl.PtrOffset(0))
+	graphics.GLState.BindVertexArray(0)
+}
+
+// UVRect calculates the uv offset and scale for a source rectangle (in texels,
+// nil = whole texture) including flipping.
+func UVRect(texture *Texture, source *rmath.Rectangle, flipX, flipY bool) (u, v, su, sv float32) {
+	u, v, su, sv = 0.0, 0.0, 1.0, 1.0
+
+	if source != nil && texture.Width > 0 && texture.Height > 0 {
+		w := float32(texture.Width)
+		h := float32(texture.Height)
+		u = source.Left / w
+		v = source.Top / h
+		su = source.Width / w
+		sv = source.Height / h
+	}
+
+	if flipX {
+		u += su
+		su = -su
+	}
+
+	if flipY {
+		v += sv
+		sv = -sv
+	}
+
+	return
+}
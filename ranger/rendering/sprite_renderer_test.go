@@ -0,0 +1,32 @@
+package rendering
+
+import (
+	"testing"
+
+	"github.com/wdevore/ranger/rmath"
+)
+
+func Test_UVRect_WholeTexture(t *testing.T) {
+	tex := &Texture{Width: 64, Height: 32}
+
+	u, v, su, sv := UVRect(tex, nil, false, false)
+
+	if u != 0.0 || v != 0.0 || su != 1.0 || sv != 1.0 {
+		t.Errorf("Expected (0, 0, 1, 1), got: (%f, %f, %f, %f)", u, v, su, sv)
+	}
+}
+
+func Test_UVRect_SourceFlipped(t *testing.T) {
+	tex := &Texture{Width: 64, Height: 32}
+	source := rmath.NewRectangleUnCentered(16.0, 8.0, 16.0, 8.0)
+
+	u, v, su, sv := UVRect(tex, source, true, true)
+
+	if !rmath.IsEqual(u, 0.5) || !rmath.IsEqual(su, -0.25) {
+		t.Errorf("Expected u = 0.5, su = -0.25, got: %f, %f", u, su)
+	}
+
+	if !rmath.IsEqual(v, 0.5) || !rmath.IsEqual(sv, -0.25) {
+		t.Errorf("Expected v = 0.5, sv = -0.25, got: %f, %f", v, sv)
+	}
+}
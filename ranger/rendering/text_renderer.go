@@ -0,0 +1,189 @@
+// Package rendering defines text mesh rendering.
+package rendering
+
+import (
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/ranger/fonts"
+	"github.com/wdevore/ranger/graphics"
+	"github.com/wdevore/ranger/rmath"
+)
+
+// textBatch holds the quads of a TextMesh that share a font page and tag.
+type textBatch struct {
+	page int
+	tag  int
+
+	mesh     Mesh
+	genBound bool
+	vaoID    uint32
+}
+
+// TextMesh is a string's glyph quads grouped by font page and tag, so a
+// string costs one draw call per page and tag it uses.
+type TextMesh struct {
+	batches []*textBatch
+
+	// TagColors colours quads by their Tag. A missing or nil colour uses
+	// the colour given to Draw.
+	TagColors []*graphics.Colors
+}
+
+type batchKey struct {
+	page, tag int
+}
+
+// NewTextMesh creates an empty TextMesh
+func NewTextMesh() *TextMesh {
+	tm := new(TextMesh)
+	return tm
+}
+
+// Build replaces the mesh with the quads and uploads it.
+func (tm *TextMesh) Build(quads []fonts.GlyphQuad) {
+	byKey := map[batchKey]*textBatch{}
+
+	// Reuse existing batches (and their buffers) where possible.
+	for _, b := range tm.batches {
+		b.mesh.Vertices = b.mesh.Vertices[:0]
+		b.mesh.Indices = b.mesh.Indices[:0]
+		byKey[batchKey{b.page, b.tag}] = b
+	}
+
+	for _, q := range quads {
+		key := batchKey{q.Page, q.Tag}
+		b, ok := byKey[key]
+		if !ok {
+			b = &textBatch{page: q.Page, tag: q.Tag}
+			b.mesh.Format = PositionUVFormat
+			byKey[key] = b
+			tm.batches = append(tm.batches, b)
+		}
+
+		base := uint32(b.mesh.VertexCount())
+
+		b.mesh.Vertices = append(b.mesh.Vertices,
+			q.X0, q.Y0, 0.0, q.U0, q.V0,
+			q.X1, q.Y0, 0.0, q.U1, q.V0,
+			q.X1, q.Y1, 0.0, q.U1, q.V1,
+			q.X0, q.Y1, 0.0, q.U0, q.V1)
+
+		b.mesh.Indices = append(b.mesh.Indices,
+			base, base+1, base+2,
+			base, base+2, base+3)
+	}
+
+	for _, b := range tm.batches {
+		if len(b.mesh.Indices) == 0 {
+			continue
+		}
+
+		if !b.genBound {
+			b.vaoID = genVertexArray()
+			b.genBound = true
+		}
+		b.mesh.GenBuffers()
+
+		b.mesh.bindLayout(b.vaoID)
+	}
+}
+
+// Delete deletes the batches' VAOs and buffers. Build creates them again.
+func (tm *TextMesh) Delete() {
+	for _, b := range tm.batches {
+		if b.genBound {
+			deleteVertexArray(b.vaoID)
+			b.vaoID = 0
+			b.genBound = false
+		}
+		b.mesh.Delete()
+	}
+	tm.batches = nil
+}
+
+// TextDrawer draws TextMeshes, for example, a TextRenderer for coverage
+// fonts or an SDFTextRenderer for distance field fonts. 'style' may be nil.
+type TextDrawer interface {
+	Draw(mesh *TextMesh, font *Font, mvp *rmath.Matrix4, color *graphics.Colors, style *TextStyle)
+}
+
+// TextRenderer draws TextMeshes. It expects a shader with:
+//   attribute 0: vec3 position
+//   attribute 1: vec2 uv
+//   uniform mat4 mvp
+//   uniform vec4 color    multiplied with the glyph coverage
+//   uniform sampler2D texture0
+type TextRenderer struct {
+	shader *Shader
+
+	mvp     Uniform
+	color   Uniform
+	texture Uniform
+}
+
+// NewTextRenderer creates a renderer using a text shader. You must call
+// Construct before drawing.
+func NewTextRenderer(shader *Shader) *TextRenderer {
+	tr := new(TextRenderer)
+	tr.shader = shader
+	return tr
+}
+
+// Construct resolves the shader's uniforms. The shader must already be loaded.
+func (tr *TextRenderer) Construct() error {
+	uniforms, err := tr.shader.Uniforms("mvp", "color")
+	if err != nil {
+		return err
+	}
+	tr.mvp, tr.color = uniforms[0], uniforms[1]
+
+	// Optional, samplers default to unit 0.
+	tr.texture, _ = tr.shader.Uniform("texture0")
+
+	return nil
+}
+
+// Draw renders a TextMesh built from the font's glyphs. Coverage fonts
+// can't be styled so 'style' is ignored.
+func (tr *TextRenderer) Draw(mesh *TextMesh, font *Font, mvp *rmath.Matrix4, color *graphics.Colors, style *TextStyle) {
+	tr.shader.Use()
+
+	tr.mvp.SetMatrix4(mvp)
+	tr.texture.SetSampler(0)
+
+	mesh.draw(font, tr.color, color, nil)
+}
+
+// draw binds each page, sets each batch's colour and draws the batch.
+// 'perPage' is called after the page's texture is bound.
+func (tm *TextMesh) draw(font *Font, colorU Uniform, color *graphics.Colors, perPage func(page *Texture)) {
+	for _, b := range tm.batches {
+		if len(b.mesh.Indices) == 0 {
+			continue
+		}
+
+		page := font.Pages[b.page]
+		page.Use(0)
+
+		c := tm.batchColor(b.tag, color)
+		colorU.SetColor(&c)
+
+		if perPage != nil {
+			perPage(page)
+		}
+
+		graphics.GLState.BindVertexArray(b.vaoID)
+		gl.DrawElements(gl.TRIANGLES, int32(len(b.mesh.Indices)), gl.UNSIGNED_INT, gl.PtrOffset(0))
+	}
+
+	graphics.GLState.BindVertexArray(0)
+}
+
+// batchColor returns a tag's colour faded by the Draw colour's alpha.
+func (tm *TextMesh) batchColor(tag int, color *graphics.Colors) graphics.Colors {
+	c := *color
+	if tag >= 0 && tag < len(tm.TagColors) && tm.TagColors[tag] != nil {
+		c = *tm.TagColors[tag]
+		c.A *= color.A
+	}
+	return c
+}
@@ -0,0 +1,186 @@
+// Package rendering defines Texture features of shaders.
+package rendering
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+	"os"
+
+	// Register decoders for image.Decode
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/ranger/graphics"
+)
+
+// TextureOptions controls how a texture is sampled.
+type TextureOptions struct {
+	// MinFilter and MagFilter are gl.NEAREST, gl.LINEAR or for MinFilter
+	// a mipmap filter, for example, gl.LINEAR_MIPMAP_LINEAR.
+	MinFilter int32
+	MagFilter int32
+
+	// WrapS and WrapT are gl.CLAMP_TO_EDGE, gl.REPEAT or gl.MIRRORED_REPEAT.
+	WrapS int32
+	WrapT int32
+
+	// Mipmaps generates mipmaps after uploading.
+	Mipmaps bool
+}
+
+// DefaultTextureOptions returns linear filtering and edge clamping.
+func DefaultTextureOptions() TextureOptions {
+	return TextureOptions{
+		MinFilter: gl.LINEAR,
+		MagFilter: gl.LINEAR,
+		WrapS:     gl.CLAMP_TO_EDGE,
+		WrapT:     gl.CLAMP_TO_EDGE,
+	}
+}
+
+// PixelTextureOptions returns nearest filtering and edge clamping, typical
+// for pixel art.
+func PixelTextureOptions() TextureOptions {
+	return TextureOptions{
+		MinFilter: gl.NEAREST,
+		MagFilter: gl.NEAREST,
+		WrapS:     gl.CLAMP_TO_EDGE,
+		WrapT:     gl.CLAMP_TO_EDGE,
+	}
+}
+
+// Texture is a 2D OpenGL texture. The first row of the image is at V = 0.0.
+type Texture struct {
+	// Indicate if an Id has been generated yet.
+	genBound bool
+
+	textureID uint32 // GLuint
+	// unit the texture was last used on
+	unit uint32
+
+	Width, Height int
+
+	options TextureOptions
+}
+
+// NewTexture creates an empty Texture
+func NewTexture(options TextureOptions) *Texture {
+	t := new(Texture)
+	t.options = options
+	return t
+}
+
+// LoadTexture decodes a PNG or JPEG file and uploads it.
+func LoadTexture(path string, options TextureOptions) (*Texture, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+
+	t := NewTexture(options)
+	err = t.Upload(img)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// GenTexture generates a texture id. Upload calls this if needed.
+func (t *Texture) GenTexture() {
+	if t.genBound {
+		return
+	}
+	gl.GenTextures(1, &t.textureID)
+	graphics.Resources.Created(graphics.TextureResource, t.textureID, "Texture")
+	t.genBound = true
+}
+
+// Delete deletes the texture. Uploading again recreates it.
+func (t *Texture) Delete() {
+	if !t.genBound {
+		return
+	}
+	gl.DeleteTextures(1, &t.textureID)
+	graphics.GLState.TextureDeleted(t.textureID)
+	graphics.Resources.Deleted(graphics.TextureResource, t.textureID)
+	t.textureID = 0
+	t.genBound = false
+}
+
+// Upload converts the image to RGBA and uploads it to the texture.
+func (t *Texture) Upload(img image.Image) error {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return errors.New("texture image is empty")
+	}
+
+	// Textures are uploaded as non-premultiplied RGBA to match the
+	// SRC_ALPHA, ONE_MINUS_SRC_ALPHA blending the Stage configures.
+	rgba, ok := img.(*image.NRGBA)
+	if !ok || rgba.Stride != rgba.Rect.Dx()*4 || bounds.Min != (image.Point{}) {
+		rgba = image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	}
+
+	if !t.genBound {
+		t.GenTexture()
+	}
+
+	t.Width = bounds.Dx()
+	t.Height = bounds.Dy()
+
+	graphics.GLState.BindTexture(0, t.textureID)
+
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8,
+		int32(t.Width), int32(t.Height), 0,
+		gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix))
+
+	t.applyOptions()
+
+	return nil
+}
+
+// SetOptions changes the sampling options of an uploaded texture.
+func (t *Texture) SetOptions(options TextureOptions) {
+	t.options = options
+
+	if !t.genBound {
+		return
+	}
+
+	graphics.GLState.BindTexture(0, t.textureID)
+	t.applyOptions()
+}
+
+func (t *Texture) applyOptions() {
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, t.options.MinFilter)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, t.options.MagFilter)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, t.options.WrapS)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, t.options.WrapT)
+
+	if t.options.Mipmaps {
+		gl.GenerateMipmap(gl.TEXTURE_2D)
+	}
+}
+
+// Use binds the texture to a texture unit, for example, 0 = gl.TEXTURE0
+func (t *Texture) Use(unit uint32) {
+	t.unit = unit
+	graphics.GLState.BindTexture(unit, t.textureID)
+}
+
+// UnUse removes the texture binding from the unit it was last used on
+// (optional)
+func (t *Texture) UnUse() {
+	graphics.GLState.BindTexture(t.unit, 0)
+}
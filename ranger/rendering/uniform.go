@@ -0,0 +1,264 @@
+// Package rendering defines typed shader uniforms.
+package rendering
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/ranger/graphics"
+	"github.com/wdevore/ranger/rmath"
+)
+
+// Uniform is a resolved uniform of a Shader's program. Values are set on
+// the program in use so call Shader.Use first. A Uniform follows its
+// Shader when it's reloaded.
+type Uniform struct {
+	Name string
+	slot *uniformSlot
+}
+
+// uniformSlot is shared by the Uniforms of a name so reloading a Shader
+// can update their location.
+type uniformSlot struct {
+	location int32
+}
+
+// Location returns the uniform's location, -1 for an unresolved uniform.
+func (u Uniform) Location() int32 {
+	if u.slot == nil {
+		return -1
+	}
+	return u.slot.location
+}
+
+// SetMatrix4 sets a mat4.
+func (u Uniform) SetMatrix4(m *rmath.Matrix4) {
+	gl.UniformMatrix4fv(u.Location(), 1, false, &m.Data()[0])
+}
+
+// SetVector3 sets a vec3.
+func (u Uniform) SetVector3(v *rmath.Vector3) {
+	gl.Uniform3f(u.Location(), v.X, v.Y, v.Z)
+}
+
+// Set2Components sets a vec2.
+func (u Uniform) Set2Components(x, y float32) {
+	gl.Uniform2f(u.Location(), x, y)
+}
+
+// Set4Components sets a vec4.
+func (u Uniform) Set4Components(x, y, z, w float32) {
+	gl.Uniform4f(u.Location(), x, y, z, w)
+}
+
+// SetFloat sets a float.
+func (u Uniform) SetFloat(v float32) {
+	gl.Uniform1f(u.Location(), v)
+}
+
+// SetInt sets an int.
+func (u Uniform) SetInt(v int32) {
+	gl.Uniform1i(u.Location(), v)
+}
+
+// SetColor sets a vec4 to r,g,b,a.
+func (u Uniform) SetColor(c *graphics.Colors) {
+	gl.Uniform4f(u.Location(), c.R, c.G, c.B, c.A)
+}
+
+// SetSampler binds a sampler to a texture unit, see Texture.Use.
+func (u Uniform) SetSampler(unit int) {
+	gl.Uniform1i(u.Location(), int32(unit))
+}
+
+// ---------------------------------------------------------------------
+// Shader lookups
+// ---------------------------------------------------------------------
+
+// Uniform returns an active uniform by name. Uniforms the compiler removed
+// because they are unused aren't active. On error the Uniform's location
+// is -1 which GL ignores, so optional uniforms can ignore the error.
+func (s *Shader) Uniform(name string) (Uniform, error) {
+	if s.slots == nil {
+		s.slots = map[string]*uniformSlot{}
+	}
+
+	slot, ok := s.slots[name]
+	if !ok {
+		slot = &uniformSlot{}
+		s.slots[name] = slot
+	}
+
+	location, ok := s.uniforms[name]
+	if !ok {
+		location = -1
+	}
+	slot.location = location
+
+	u := Uniform{Name: name, slot: slot}
+	if !ok {
+		return u, fmt.Errorf("shader (%s, %s) has no active uniform '%s'", s.vertexSrc, s.fragmentSrc, name)
+	}
+
+	return u, nil
+}
+
+// Uniforms resolves several uniforms at once. The error names every
+// missing uniform.
+func (s *Shader) Uniforms(names ...string) ([]Uniform, error) {
+	uniforms := make([]Uniform, len(names))
+	missing := []string{}
+
+	for i, name := range names {
+		var err error
+		uniforms[i], err = s.Uniform(name)
+		if err != nil {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return uniforms, fmt.Errorf("shader (%s, %s) is missing the uniforms: %s",
+			s.vertexSrc, s.fragmentSrc, strings.Join(missing, ", "))
+	}
+
+	return uniforms, nil
+}
+
+// AttributeLocation returns the location of an active vertex attribute.
+func (s *Shader) AttributeLocation(name string) (uint32, error) {
+	location, ok := s.attributes[name]
+	if !ok {
+		return 0, fmt.Errorf("shader (%s, %s) has no active attribute '%s'", s.vertexSrc, s.fragmentSrc, name)
+	}
+
+	return uint32(location), nil
+}
+
+// SetMatrix4 sets a mat4 uniform by name. The Shader must be in use, as
+// for all of the Set methods.
+func (s *Shader) SetMatrix4(name string, m *rmath.Matrix4) error {
+	u, err := s.Uniform(name)
+	if err == nil {
+		u.SetMatrix4(m)
+	}
+	return err
+}
+
+// SetVector3 sets a vec3 uniform by name.
+func (s *Shader) SetVector3(name string, v *rmath.Vector3) error {
+	u, err := s.Uniform(name)
+	if err == nil {
+		u.SetVector3(v)
+	}
+	return err
+}
+
+// SetFloat sets a float uniform by name.
+func (s *Shader) SetFloat(name string, v float32) error {
+	u, err := s.Uniform(name)
+	if err == nil {
+		u.SetFloat(v)
+	}
+	return err
+}
+
+// SetInt sets an int uniform by name.
+func (s *Shader) SetInt(name string, v int32) error {
+	u, err := s.Uniform(name)
+	if err == nil {
+		u.SetInt(v)
+	}
+	return err
+}
+
+// SetColor sets a vec4 uniform by name.
+func (s *Shader) SetColor(name string, c *graphics.Colors) error {
+	u, err := s.Uniform(name)
+	if err == nil {
+		u.SetColor(c)
+	}
+	return err
+}
+
+// SetSampler binds a sampler uniform, by name, to a texture unit.
+func (s *Shader) SetSampler(name string, unit int) error {
+	u, err := s.Uniform(name)
+	if err == nil {
+		u.SetSampler(unit)
+	}
+	return err
+}
+
+// introspect caches the locations of the program's active uniforms and
+// attributes.
+func (s *Shader) introspect() {
+	s.uniforms = map[string]int32{}
+	s.attributes = map[string]int32{}
+
+	var count, maxLength int32
+	var size int32
+	var xtype uint32
+
+	gl.GetProgramiv(s.program, gl.ACTIVE_UNIFORMS, &count)
+	gl.GetProgramiv(s.program, gl.ACTIVE_UNIFORM_MAX_LENGTH, &maxLength)
+
+	for i := int32(0); i < count; i++ {
+		name := activeName(maxLength, func(length *int32, buf *uint8) {
+			gl.GetActiveUniform(s.program, uint32(i), maxLength, length, &size, &xtype, buf)
+		})
+		s.cacheLocation(s.uniforms, name, gl.GetUniformLocation(s.program, gl.Str(name+"\x00")))
+	}
+
+	gl.GetProgramiv(s.program, gl.ACTIVE_ATTRIBUTES, &count)
+	gl.GetProgramiv(s.program, gl.ACTIVE_ATTRIBUTE_MAX_LENGTH, &maxLength)
+
+	for i := int32(0); i < count; i++ {
+		name := activeName(maxLength, func(length *int32, buf *uint8) {
+			gl.GetActiveAttrib(s.program, uint32(i), maxLength, length, &size, &xtype, buf)
+		})
+		s.cacheLocation(s.attributes, name, gl.GetAttribLocation(s.program, gl.Str(name+"\x00")))
+	}
+
+	s.updateSlots()
+}
+
+// updateSlots moves Uniforms handed out before a reload to their new
+// locations.
+func (s *Shader) updateSlots() {
+	for name, slot := range s.slots {
+		location, ok := s.uniforms[name]
+		if !ok {
+			location = -1
+		}
+		slot.location = location
+	}
+}
+
+// activeName reads a name written by a glGetActive* call.
+func activeName(maxLength int32, get func(length *int32, buf *uint8)) string {
+	if maxLength < 1 {
+		return ""
+	}
+
+	buf := make([]uint8, maxLength)
+	var length int32
+	get(&length, &buf[0])
+
+	return string(buf[:length])
+}
+
+// cacheLocation records a location. Arrays are reported as "name[0]" and
+// are also cached as "name". Built-ins (gl_*) have no location.
+func (s *Shader) cacheLocation(cache map[string]int32, name string, location int32) {
+	if name == "" || location < 0 {
+		return
+	}
+
+	cache[name] = location
+
+	if strings.HasSuffix(name, "[0]") {
+		cache[strings.TrimSuffix(name, "[0]")] = location
+	}
+}
@@ -0,0 +1,67 @@
+package rendering
+
+import (
+	"strings"
+	"testing"
+)
+
+func newCachedShader() *Shader {
+	s := NewShader("test.vert", "test.frag")
+	s.uniforms = map[string]int32{}
+	s.attributes = map[string]int32{}
+
+	s.cacheLocation(s.uniforms, "mvp", 0)
+	s.cacheLocation(s.uniforms, "lights[0]", 3)
+	s.cacheLocation(s.uniforms, "gl_FragCoord", -1)
+	s.cacheLocation(s.attributes, "aPos", 0)
+
+	return s
+}
+
+func Test_Shader_Uniform(t *testing.T) {
+	s := newCachedShader()
+
+	u, err := s.Uniform("mvp")
+	if err != nil || u.Location() != 0 {
+		t.Errorf("Expected mvp at 0, got: %d %v", u.Location(), err)
+	}
+
+	u, err = s.Uniform("lights")
+	if err != nil || u.Location() != 3 {
+		t.Errorf("Expected the array 'lights' at 3, got: %d %v", u.Location(), err)
+	}
+
+	u, err = s.Uniform("color")
+	if err == nil || u.Location() != -1 {
+		t.Errorf("Expected an error and location -1 for 'color', got: %d %v", u.Location(), err)
+	}
+
+	if _, err = s.Uniform("gl_FragCoord"); err == nil {
+		t.Errorf("Expected built-ins to be unknown")
+	}
+}
+
+func Test_Shader_Uniforms_Missing(t *testing.T) {
+	s := newCachedShader()
+
+	_, err := s.Uniforms("mvp", "tint", "uvRect")
+	if err == nil {
+		t.Fatalf("Expected an error for missing uniforms")
+	}
+
+	if !strings.Contains(err.Error(), "tint, uvRect") {
+		t.Errorf("Expected the error to name tint and uvRect, got: %s", err.Error())
+	}
+}
+
+func Test_Shader_AttributeLocation(t *testing.T) {
+	s := newCachedShader()
+
+	if loc, err := s.AttributeLocation("aPos"); err != nil || loc != 0 {
+		t.Errorf("Expected aPos at 0, got: %d %v", loc, err)
+	}
+
+	if _, err := s.AttributeLocation("aUV"); err == nil {
+		t.Errorf("Expected an error for aUV")
+	}
+}
@@ -1,14 +1,10 @@
 package rendering
 
 import (
-	"unsafe"
-
 	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/ranger/graphics"
 )
 
-const xyzComponentCount int32 = 3
-const attributeIndex uint32 = 0
-
 // VAO defines a Vertex Array Object
 type VAO struct {
 	// Indicates if an Id has been generated
@@ -24,33 +20,77 @@ func NewVAO(m *Mesh) *VAO {
 	return v
 }
 
-// Bind setups the VAO and Mesh
+// Bind setups the VAO and Mesh using the Mesh's VertexFormat
 func (v *VAO) Bind() {
 	if !v.genBound {
-		gl.GenVertexArrays(1, &v.vaoID)
+		v.vaoID = genVertexArray()
+		v.genBound = true
 	}
-
 	v.mesh.GenBuffers()
 
 	// Bind the Vertex Array Object first, then bind and set vertex buffer(s)
 	// and attribute pointer(s).
-	gl.BindVertexArray(v.vaoID)
+	v.mesh.bindLayout(v.vaoID)
+}
+
+// Delete deletes the VAO and its Mesh's buffers. Binding again recreates
+// them.
+func (v *VAO) Delete() {
+	if v.genBound {
+		deleteVertexArray(v.vaoID)
+		v.vaoID = 0
+		v.genBound = false
+	}
+	v.mesh.Delete()
+}
+
+// genVertexArray generates a VAO and records it.
+func genVertexArray() uint32 {
+	var vaoID uint32
+	gl.GenVertexArrays(1, &vaoID)
+	graphics.Resources.Created(graphics.VertexArrayResource, vaoID, "VAO")
+	return vaoID
+}
 
-	v.mesh.Bind()
+// deleteVertexArray deletes a VAO generated by genVertexArray.
+func deleteVertexArray(vaoID uint32) {
+	gl.DeleteVertexArrays(1, &vaoID)
+	graphics.GLState.VertexArrayDeleted(vaoID)
+	graphics.Resources.Deleted(graphics.VertexArrayResource, vaoID)
+}
 
-	arrayCount := xyzComponentCount * int32(unsafe.Sizeof(float32(0)))
-	gl.VertexAttribPointer(attributeIndex, int32(xyzComponentCount), gl.FLOAT, false, arrayCount, gl.PtrOffset(0))
+// Update uploads the Mesh after vertices or indices were appended or it
+// was rebuilt. Formats with separate streams record their layout again
+// when the vertex count changes.
+func (v *VAO) Update() {
+	if !v.genBound {
+		v.Bind()
+		return
+	}
 
-	gl.EnableVertexAttribArray(0)
+	graphics.GLState.BindVertexArray(v.vaoID)
 
-	// Note that this is allowed, the call to glVertexAttribPointer registered VBO as the currently bound
-	// vertex buffer object so afterwards we can safely unbind
-	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	if v.mesh.Update() {
+		v.mesh.VertexFormat().apply(v.mesh.VertexCount())
+	}
 
-	// Unbind VAO (it's always a good thing to unbind any buffer/array to prevent strange bugs),
-	// remember: do NOT unbind the EBO, keep it bound to this VAO
-	gl.BindVertexArray(0)
+	graphics.GLState.BindBuffer(gl.ARRAY_BUFFER, 0)
+	graphics.GLState.BindVertexArray(0)
+}
 
+// UpdateVertices uploads 'count' vertices, starting at 'first', that were
+// changed in place, for example, particles moved this frame.
+func (v *VAO) UpdateVertices(first, count int) {
+	v.mesh.UpdateVertices(first, count)
+	graphics.GLState.BindBuffer(gl.ARRAY_BUFFER, 0)
+}
+
+// UpdateIndices uploads 'count' indices, starting at 'first', that were
+// changed in place.
+func (v *VAO) UpdateIndices(first, count int) {
+	graphics.GLState.BindVertexArray(v.vaoID)
+	v.mesh.UpdateIndices(first, count)
+	graphics.GLState.BindVertexArray(0)
 }
 
 // Render shape using VAO
@@ -69,9 +109,15 @@ func (v *VAO) Render(vs *VectorShape) {
 	gl.DrawElements(vs.PrimitiveMode, vs.Count, uint32(gl.UNSIGNED_INT), gl.PtrOffset(vs.Offset()))
 }
 
+// RenderInstanced draws 'count' instances of a shape using the currently
+// bound VAO, typically an InstanceBuffer's.
+func (v *VAO) RenderInstanced(vs *VectorShape, count int) {
+	gl.DrawElementsInstanced(vs.PrimitiveMode, vs.Count, uint32(gl.UNSIGNED_INT), gl.PtrOffset(vs.Offset()), int32(count))
+}
+
 // Use bind vertex array to Id
 func (v *VAO) Use() {
-	gl.BindVertexArray(v.vaoID)
+	graphics.GLState.BindVertexArray(v.vaoID)
 }
 
 // UnUse removes the array binding (optional)
@@ -79,7 +125,7 @@ func (v *VAO) UnUse() {
 	// See opengl wiki as to why "glBindVertexArray(0)" isn't really necessary here:
 	// https://www.opengl.org/wiki/Vertex_Specification#Vertex_Buffer_Object
 	// Note the line "Changing the GL_ARRAY_BUFFER binding changes nothing about vertex attribute 0..."
-	gl.BindVertexArray(0)
+	graphics.GLState.BindVertexArray(0)
 }
 
 // void VAO::draw(int primitiveType, int offset, int count) {
@@ -5,14 +5,20 @@ import (
 	"unsafe"
 
 	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/ranger/graphics"
 )
 
+const floatSize = int(unsafe.Sizeof(float32(0)))
+
 // VBO represents a shader's VBO features.
 type VBO struct {
 	// Indicate if an Id has been generated yet.
 	genBound bool
 
 	vboID uint32 // GLuint
+
+	// capacity of the buffer's storage in bytes
+	capacity int
 }
 
 // NewVBO creates a empty VBO
@@ -22,20 +28,72 @@ func NewVBO() *VBO {
 	return b
 }
 
-// GenBuffer generates a buffer id for buffer data.
+// GenBuffer generates a buffer id for buffer data, once.
 // Call this BEFORE you call Bind.
 func (b *VBO) GenBuffer() {
+	if b.genBound {
+		return
+	}
 	gl.GenBuffers(1, &b.vboID)
+	graphics.Resources.Created(graphics.BufferResource, b.vboID, "VBO")
 	b.genBound = true
 }
 
-// Bind binds the buffer id against the mesh vertices
-func (b *VBO) Bind(m *Mesh) {
+// Delete deletes the buffer. It can be generated again.
+func (b *VBO) Delete() {
+	if !b.genBound {
+		return
+	}
+	gl.DeleteBuffers(1, &b.vboID)
+	graphics.GLState.BufferDeleted(b.vboID)
+	graphics.Resources.Deleted(graphics.BufferResource, b.vboID)
+	b.vboID = 0
+	b.capacity = 0
+	b.genBound = false
+}
+
+// Bind binds the buffer id against the mesh vertices and uploads all of
+// them. It returns true if the buffer's storage was (re)allocated.
+func (b *VBO) Bind(m *Mesh) bool {
 	if !b.genBound {
 		panic("A VBO buffer ID has not been generated. Call GenBuffer first.")
 	}
 
-	gl.BindBuffer(gl.ARRAY_BUFFER, b.vboID)
-	floatSize := int(unsafe.Sizeof(float32(0)))
-	gl.BufferData(gl.ARRAY_BUFFER, len(m.Vertices)*floatSize, gl.Ptr(m.Vertices), gl.STATIC_DRAW)
+	graphics.GLState.BindBuffer(gl.ARRAY_BUFFER, b.vboID)
+
+	previous := b.capacity
+	b.capacity = uploadBuffer(gl.ARRAY_BUFFER, b.capacity, len(m.Vertices)*floatSize, ptr(m.Vertices), m.Usage)
+
+	return b.capacity != previous
+}
+
+// Update uploads 'count' vertex components starting at component 'first'.
+// If they don't fit the buffer everything is uploaded as with Bind, and
+// true is returned.
+func (b *VBO) Update(m *Mesh, first, count int) bool {
+	if (first+count)*floatSize > b.capacity {
+		return b.Bind(m)
+	}
+
+	graphics.GLState.BindBuffer(gl.ARRAY_BUFFER, b.vboID)
+	updateBuffer(gl.ARRAY_BUFFER, first*floatSize, count*floatSize, ptr(m.Vertices[first:first+count]))
+
+	return false
+}
+
+// ptr returns a pointer to a slice's data, nil when it's empty.
+func ptr(data interface{}) unsafe.Pointer {
+	switch d := data.(type) {
+	case []float32:
+		if len(d) == 0 {
+			return nil
+		}
+		return gl.Ptr(d)
+	case []uint32:
+		if len(d) == 0 {
+			return nil
+		}
+		return gl.Ptr(d)
+	}
+	return gl.Ptr(data)
 }
@@ -1,11 +1,14 @@
 package rendering
 
+import (
+	"fmt"
+)
+
 // VectorAtlas helps managing a Mesh. It is abstract and
-// should be embedded.
+// should be embedded. Non static atlases can be rebuilt or appended to
+// after they are bound, see VectorObject.Update.
 type VectorAtlas struct {
-	hasNormals bool
-	hasColors  bool
-	isStatic   bool
+	isStatic bool
 	// vertexIdx          int
 	// vertexSize         int
 
@@ -21,17 +24,103 @@ type VectorAtlas struct {
 // No Allocator as this type is abstract and meant to
 // be embedded
 
-// Initialize sets defaults
+// Initialize sets defaults. Vertices are positions optionally followed by
+// an RGBA colour.
 func (va *VectorAtlas) Initialize(isStatic, hasColors bool) {
-	va.hasColors = hasColors
+	format := PositionFormat
+	if hasColors {
+		format = PositionColorFormat
+	}
+	va.InitializeFormat(isStatic, format)
+}
+
+// InitializeFormat sets defaults with any interleaved vertex format.
+func (va *VectorAtlas) InitializeFormat(isStatic bool, format *VertexFormat) {
 	va.isStatic = isStatic
+	va.mesh.Format = format
+
+	if isStatic {
+		va.mesh.Usage = StaticUsage
+	} else {
+		va.mesh.Usage = DynamicUsage
+	}
+}
+
+// IsStatic returns true if the atlas is uploaded once.
+func (va *VectorAtlas) IsStatic() bool {
+	return va.isStatic
+}
+
+// SetUsage changes how the atlas' buffers are used, for example,
+// StreamUsage for geometry rebuilt every frame. Call before binding.
+func (va *VectorAtlas) SetUsage(usage BufferUsage) {
+	va.mesh.Usage = usage
+	va.isStatic = usage.glUsage() == StaticUsage.glUsage()
+}
+
+// Usage returns how the atlas' buffers are used.
+func (va *VectorAtlas) Usage() BufferUsage {
+	return va.mesh.Usage
+}
+
+// Reset removes all vertices and indices so the atlas can be rebuilt.
+// Shapes already added refer to indices that no longer exist.
+func (va *VectorAtlas) Reset() {
+	va.mesh.Vertices = va.mesh.Vertices[:0]
+	va.mesh.Indices = va.mesh.Indices[:0]
+	va.ComponentCount = 0
+	va.Idx = 0
+	va.prevComponentCount = 0
+	va.prevIndexCount = 0
+}
+
+// SetVertex replaces the position of an existing vertex. Upload the change
+// with VectorObject.UpdateVertices.
+func (va *VectorAtlas) SetVertex(index int, x, y, z float32) {
+	i := index * va.mesh.VertexFormat().ComponentCount()
+	va.mesh.Vertices[i] = x
+	va.mesh.Vertices[i+1] = y
+	va.mesh.Vertices[i+2] = z
+}
+
+// HasColors returns true if vertices have a colour.
+func (va *VectorAtlas) HasColors() bool {
+	return va.mesh.VertexFormat().Attribute(ColorAttribute.Name) != nil
+}
+
+// HasNormals returns true if vertices have a normal.
+func (va *VectorAtlas) HasNormals() bool {
+	return va.mesh.VertexFormat().Attribute(NormalAttribute.Name) != nil
+}
+
+// Format returns the vertex format.
+func (va *VectorAtlas) Format() *VertexFormat {
+	return va.mesh.VertexFormat()
 }
 
-// AddVertex adds a vertex to the mesh
+// AddVertex adds a vertex to the mesh and returns its index. Components
+// after the position, if any, are zero.
 func (va *VectorAtlas) AddVertex(x, y, z float32) int {
 	va.mesh.Vertices = append(va.mesh.Vertices, x, y, z)
+
+	for i := 3; i < va.mesh.VertexFormat().ComponentCount(); i++ {
+		va.mesh.Vertices = append(va.mesh.Vertices, 0.0)
+	}
+
+	va.ComponentCount++
+	return va.ComponentCount - 1
+}
+
+// AddVertexComponents adds a vertex given all of its format's components
+// and returns its index.
+func (va *VectorAtlas) AddVertexComponents(components ...float32) int {
+	if len(components) != va.mesh.VertexFormat().ComponentCount() {
+		panic(fmt.Sprintf("expected %d vertex components, got: %d", va.mesh.VertexFormat().ComponentCount(), len(components)))
+	}
+
+	va.mesh.Vertices = append(va.mesh.Vertices, components...)
 	va.ComponentCount++
-	return va.ComponentCount
+	return va.ComponentCount - 1
 }
 
 // AddIndex adds an index to the mesh
@@ -0,0 +1,344 @@
+// Package rendering defines a batching VectorShape renderer.
+package rendering
+
+import (
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/ranger/graphics"
+	"github.com/wdevore/ranger/rmath"
+)
+
+// VectorShapeDrawer draws VectorShapes, either immediately with a
+// VectorShapeRenderer or collected by a VectorBatchRenderer.
+type VectorShapeDrawer interface {
+	Draw(vo *VectorObject, shape *VectorShape, mvp *rmath.Matrix4, color *graphics.Colors)
+}
+
+// BatchState is what a batch's shapes share. Changing any of it starts a
+// new batch.
+type BatchState struct {
+	// Shader must have the vector_color shader's attributes and uniforms.
+	Shader *Shader
+	// Texture is optional, it is bound to unit 0.
+	Texture *Texture
+	Blend   BlendMode
+}
+
+// vectorBatch is a run of indices, in the renderer's mesh, drawn with one
+// call.
+type vectorBatch struct {
+	state     BatchState
+	primitive uint32
+	first     int
+	count     int
+}
+
+// shapeUniforms are the uniforms of a shader drawing shapes.
+type shapeUniforms struct {
+	mvp   Uniform
+	color Uniform
+}
+
+// shapeUniformCache resolves shaders' shapeUniforms once. Uniforms follow
+// their Shader when it's reloaded. A failure is reported once and kept
+// until the Shader is reloaded.
+type shapeUniformCache struct {
+	owner    string
+	resolved map[*Shader]*shapeUniforms
+	failed   map[*Shader]uniformFailure
+}
+
+// uniformFailure is an error and the Shader's Load count at the time.
+type uniformFailure struct {
+	loads int
+	err   error
+}
+
+func newShapeUniformCache(owner string) *shapeUniformCache {
+	uc := new(shapeUniformCache)
+	uc.owner = owner
+	uc.resolved = map[*Shader]*shapeUniforms{}
+	uc.failed = map[*Shader]uniformFailure{}
+	return uc
+}
+
+func (uc *shapeUniformCache) get(shader *Shader) (*shapeUniforms, error) {
+	if su, ok := uc.resolved[shader]; ok {
+		return su, nil
+	}
+
+	if f, ok := uc.failed[shader]; ok && f.loads == shader.loads {
+		return nil, f.err
+	}
+
+	uniforms, err := shader.Uniforms("mvp", "color")
+	if err != nil {
+		println(uc.owner, ": ", err.Error())
+		uc.failed[shader] = uniformFailure{loads: shader.loads, err: err}
+		return nil, err
+	}
+	delete(uc.failed, shader)
+
+	su := &shapeUniforms{mvp: uniforms[0], color: uniforms[1]}
+	uc.resolved[shader] = su
+	return su, nil
+}
+
+// VectorBatchRenderer collects the VectorShapes drawn during a scene's
+// traversal and draws them in as few draw calls as possible. Vertices are
+// transformed and coloured on the CPU into a single stream mesh, so shapes
+// of any VectorObject can share a call. Strips, loops and fans become
+// lines and triangles, so a batch only breaks when the BatchState or the
+// kind of primitive (points, lines or triangles) changes. Draw order is
+// kept.
+//
+// Call Flush after the traversal, for example, via RenderView.Flushers.
+// Matrices must be affine, as orthographic projections are.
+type VectorBatchRenderer struct {
+	state BatchState
+
+	uniforms *shapeUniformCache
+
+	batches []vectorBatch
+
+	mesh Mesh
+	vao  *VAO
+
+	// DrawCalls made by the last Flush.
+	DrawCalls int
+
+	// Scratch
+	point   rmath.Vector3
+	indices []uint32
+}
+
+// NewVectorBatchRenderer creates a renderer whose shapes default to the
+// given shader (typically NewVectorColorShader) and alpha blending. You
+// must call Construct before drawing.
+func NewVectorBatchRenderer(shader *Shader) *VectorBatchRenderer {
+	br := new(VectorBatchRenderer)
+	br.state.Shader = shader
+	br.state.Blend = BlendAlpha
+	br.uniforms = newShapeUniformCache("VectorBatchRenderer")
+
+	br.mesh.Format = PositionColorFormat
+	br.mesh.Usage = StreamUsage
+	br.vao = NewVAO(&br.mesh)
+	return br
+}
+
+// Construct resolves the default shader's uniforms. The shader must
+// already be loaded.
+func (br *VectorBatchRenderer) Construct() error {
+	_, err := br.uniforms.get(br.state.Shader)
+	return err
+}
+
+// Delete deletes the batch mesh's VAO and buffers. Shaders aren't deleted
+// as they may be shared.
+func (br *VectorBatchRenderer) Delete() {
+	br.vao.Delete()
+}
+
+// State returns the state given to shapes drawn from now on.
+func (br *VectorBatchRenderer) State() BatchState {
+	return br.state
+}
+
+// SetState changes the state given to shapes drawn from now on.
+func (br *VectorBatchRenderer) SetState(state BatchState) {
+	br.state = state
+}
+
+// SetBlend changes the blend mode of shapes drawn from now on.
+func (br *VectorBatchRenderer) SetBlend(blend BlendMode) {
+	br.state.Blend = blend
+}
+
+// Draw transforms a shape of the vector object by 'mvp' and adds it to the
+// current batch. Vertex colours, if the object has them, are tinted by
+// 'color'. Nothing is drawn until Flush.
+func (br *VectorBatchRenderer) Draw(vo *VectorObject, shape *VectorShape, mvp *rmath.Matrix4, color *graphics.Colors) {
+	src := vo.vao.mesh
+
+	first := shape.Offset() / indexSize
+	shapeIndices := src.Indices[first : first+int(shape.Count)]
+	if len(shapeIndices) == 0 {
+		return
+	}
+
+	br.indices = expandIndices(shape.PrimitiveMode, shapeIndices, br.indices[:0])
+	if len(br.indices) == 0 {
+		return
+	}
+
+	// The vertices the shape uses.
+	low, high := shapeIndices[0], shapeIndices[0]
+	for _, i := range shapeIndices {
+		if i < low {
+			low = i
+		}
+		if i > high {
+			high = i
+		}
+	}
+
+	base := uint32(br.mesh.VertexCount())
+
+	format := src.VertexFormat()
+	vertexCount := src.VertexCount()
+	position := format.attributeIndex(PositionAttribute.Name)
+	if position < 0 {
+		position = 0
+	}
+	components := format.Attributes[position].Components
+	colorAttr := format.attributeIndex(ColorAttribute.Name)
+
+	for v := int(low); v <= int(high); v++ {
+		p := componentIndex(format, position, v, vertexCount)
+		br.point.Set3Components(src.Vertices[p], src.Vertices[p+1], 0.0)
+		if components > 2 {
+			br.point.Z = src.Vertices[p+2]
+		}
+		br.point.Mul(mvp)
+
+		r, g, b, a := color.R, color.G, color.B, color.A
+		if colorAttr >= 0 {
+			c := componentIndex(format, colorAttr, v, vertexCount)
+			r *= src.Vertices[c]
+			g *= src.Vertices[c+1]
+			b *= src.Vertices[c+2]
+			a *= src.Vertices[c+3]
+		}
+
+		br.mesh.Vertices = append(br.mesh.Vertices, br.point.X, br.point.Y, br.point.Z, r, g, b, a)
+	}
+
+	for _, i := range br.indices {
+		br.mesh.Indices = append(br.mesh.Indices, base+i-low)
+	}
+
+	br.add(primitiveClass(shape.PrimitiveMode), len(br.indices))
+}
+
+// add extends the current batch by 'count' indices or starts a new one.
+func (br *VectorBatchRenderer) add(primitive uint32, count int) {
+	n := len(br.batches)
+	if n > 0 && br.batches[n-1].state == br.state && br.batches[n-1].primitive == primitive {
+		br.batches[n-1].count += count
+		return
+	}
+
+	br.batches = append(br.batches, vectorBatch{
+		state:     br.state,
+		primitive: primitive,
+		first:     len(br.mesh.Indices) - count,
+		count:     count,
+	})
+}
+
+// Batches returns how many draw calls a Flush would make now.
+func (br *VectorBatchRenderer) Batches() int {
+	return len(br.batches)
+}
+
+// Flush uploads the collected shapes, draws each batch and starts over.
+// It restores the Stage's alpha blending.
+func (br *VectorBatchRenderer) Flush() {
+	br.DrawCalls = 0
+	if len(br.batches) == 0 {
+		return
+	}
+
+	br.vao.Update()
+	br.vao.Use()
+
+	var identity rmath.Matrix4
+	identity.ToIdentity()
+
+	var current *Shader
+	for _, b := range br.batches {
+		if b.state.Shader != current {
+			bu, err := br.uniforms.get(b.state.Shader)
+			if err != nil {
+				continue
+			}
+
+			current = b.state.Shader
+			current.Use()
+			// Vertices are already transformed and coloured.
+			bu.mvp.SetMatrix4(&identity)
+			bu.color.SetColor(graphics.White)
+		}
+
+		if b.state.Texture != nil {
+			b.state.Texture.Use(0)
+		}
+		b.state.Blend.Apply()
+
+		gl.DrawElements(b.primitive, int32(b.count), gl.UNSIGNED_INT, gl.PtrOffset(b.first*indexSize))
+		br.DrawCalls++
+	}
+
+	br.vao.UnUse()
+	BlendAlpha.Apply()
+
+	br.batches = br.batches[:0]
+	br.mesh.Vertices = br.mesh.Vertices[:0]
+	br.mesh.Indices = br.mesh.Indices[:0]
+}
+
+// componentIndex returns where an attribute of a vertex starts in a
+// Mesh's Vertices.
+func componentIndex(format *VertexFormat, attribute, vertex, vertexCount int) int {
+	stride := int(format.Stride(attribute)) / floatSize
+	return format.Offset(attribute, vertexCount)/floatSize + vertex*stride
+}
+
+// primitiveClass returns the independent primitive a mode is drawn as in
+// a batch.
+func primitiveClass(mode uint32) uint32 {
+	switch mode {
+	case gl.LINES, gl.LINE_STRIP, gl.LINE_LOOP:
+		return gl.LINES
+	case gl.TRIANGLES, gl.TRIANGLE_STRIP, gl.TRIANGLE_FAN:
+		return gl.TRIANGLES
+	}
+	return gl.POINTS
+}
+
+// expandIndices appends to 'out' the indices of 'src', drawn with 'mode',
+// as independent primitives of primitiveClass(mode).
+func expandIndices(mode uint32, src []uint32, out []uint32) []uint32 {
+	n := len(src)
+
+	switch mode {
+	case gl.LINE_STRIP, gl.LINE_LOOP:
+		for i := 0; i+1 < n; i++ {
+			out = append(out, src[i], src[i+1])
+		}
+		if mode == gl.LINE_LOOP && n > 2 {
+			out = append(out, src[n-1], src[0])
+		}
+	case gl.TRIANGLE_STRIP:
+		for i := 0; i+2 < n; i++ {
+			// Keep the winding of odd triangles.
+			if i%2 == 0 {
+				out = append(out, src[i], src[i+1], src[i+2])
+			} else {
+				out = append(out, src[i+1], src[i], src[i+2])
+			}
+		}
+	case gl.TRIANGLE_FAN:
+		for i := 1; i+1 < n; i++ {
+			out = append(out, src[0], src[i], src[i+1])
+		}
+	case gl.LINES:
+		out = append(out, src[:n-n%2]...)
+	case gl.TRIANGLES:
+		out = append(out, src[:n-n%3]...)
+	default:
+		out = append(out, src...)
+	}
+
+	return out
+}
@@ -0,0 +1,147 @@
+package rendering
+
+import (
+	"testing"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/ranger/graphics"
+	"github.com/wdevore/ranger/rmath"
+)
+
+func Test_Batch_ExpandIndices(t *testing.T) {
+	tests := []struct {
+		mode     uint32
+		expected []uint32
+	}{
+		{gl.LINE_STRIP, []uint32{0, 1, 1, 2, 2, 3}},
+		{gl.LINE_LOOP, []uint32{0, 1, 1, 2, 2, 3, 3, 0}},
+		{gl.TRIANGLE_STRIP, []uint32{0, 1, 2, 2, 1, 3}},
+		{gl.TRIANGLE_FAN, []uint32{0, 1, 2, 0, 2, 3}},
+		{gl.LINES, []uint32{0, 1, 2, 3}},
+		{gl.TRIANGLES, []uint32{0, 1, 2}},
+	}
+
+	for _, test := range tests {
+		got := expandIndices(test.mode, []uint32{0, 1, 2, 3}, nil)
+		if len(got) != len(test.expected) {
+			t.Errorf("Expected mode %d indices %v, got: %v", test.mode, test.expected, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.expected[i] {
+				t.Errorf("Expected mode %d indices %v, got: %v", test.mode, test.expected, got)
+				break
+			}
+		}
+	}
+
+	if primitiveClass(gl.LINE_LOOP) != gl.LINES || primitiveClass(gl.TRIANGLE_FAN) != gl.TRIANGLES {
+		t.Errorf("Expected loops to batch as lines and fans as triangles")
+	}
+}
+
+// batchSquare builds a vector object with a square outline and a filled
+// square.
+func batchSquare() (*VectorObject, *VectorShape, *VectorShape) {
+	vo := NewVectorObject()
+	vo.Construct()
+
+	ua := vo.UniAtlas
+	outline := NewVectorShape()
+	outline.PrimitiveMode = gl.LINE_LOOP
+	outline.SetOffset(ua.Begin())
+	ua.AddVertex(-0.5, -0.5, 0.0)
+	ua.AddIndex(0)
+	ua.AddVertex(0.5, -0.5, 0.0)
+	ua.AddIndex(1)
+	ua.AddVertex(0.5, 0.5, 0.0)
+	ua.AddIndex(2)
+	ua.AddVertex(-0.5, 0.5, 0.0)
+	ua.AddIndex(3)
+	outline.Count = int32(ua.End())
+
+	filled := NewVectorShape()
+	filled.PrimitiveMode = gl.TRIANGLE_FAN
+	filled.SetOffset(ua.Begin())
+	for i := 0; i < 4; i++ {
+		ua.AddIndex(i)
+	}
+	filled.Count = int32(ua.End())
+
+	return vo, outline, filled
+}
+
+func Test_Batch_Draw(t *testing.T) {
+	vo, outline, filled := batchSquare()
+
+	shader := NewShader("a.vert", "a.frag")
+	br := NewVectorBatchRenderer(shader)
+
+	var mvp rmath.Matrix4
+	mvp.SetTranslate3Comp(10.0, 20.0, 0.0)
+
+	for i := 0; i < 100; i++ {
+		br.Draw(vo, outline, &mvp, graphics.White)
+	}
+
+	if br.Batches() != 1 {
+		t.Errorf("Expected 1 batch, got: %d", br.Batches())
+	}
+	if br.mesh.VertexCount() != 400 {
+		t.Errorf("Expected 400 vertices, got: %d", br.mesh.VertexCount())
+	}
+	if len(br.mesh.Indices) != 800 {
+		t.Errorf("Expected 800 indices, got: %d", len(br.mesh.Indices))
+	}
+
+	// Transformed and coloured.
+	v := br.mesh.Vertices[7:14]
+	if v[0] != 10.5 || v[1] != 19.5 || v[6] != 1.0 {
+		t.Errorf("Expected vertex 10.5,19.5 opaque, got: %v", v)
+	}
+	// The second square's indices follow the first's vertices.
+	if br.mesh.Indices[8] != 4 {
+		t.Errorf("Expected index 4, got: %d", br.mesh.Indices[8])
+	}
+
+	// Primitive changes break the batch.
+	br.Draw(vo, filled, &mvp, graphics.White)
+	if br.Batches() != 2 {
+		t.Errorf("Expected 2 batches, got: %d", br.Batches())
+	}
+
+	// So do blend changes.
+	br.SetBlend(BlendAdditive)
+	br.Draw(vo, filled, &mvp, graphics.White)
+	br.Draw(vo, filled, &mvp, graphics.White)
+	if br.Batches() != 3 {
+		t.Errorf("Expected 3 batches, got: %d", br.Batches())
+	}
+
+	if br.batches[2].first != 806 || br.batches[2].count != 12 {
+		t.Errorf("Expected batch 806+12, got: %d+%d", br.batches[2].first, br.batches[2].count)
+	}
+}
+
+func Test_ShapeUniformCache_Reload(t *testing.T) {
+	shader := NewShader("a.vert", "a.frag")
+	uc := newShapeUniformCache("test")
+
+	// Not loaded so the uniforms don't resolve.
+	if _, err := uc.get(shader); err == nil {
+		t.Fatal("Expected an error for missing uniforms")
+	}
+
+	// Fixed, but not reloaded yet.
+	shader.uniforms = map[string]int32{"mvp": 0, "color": 1}
+	if _, err := uc.get(shader); err == nil {
+		t.Error("Expected the failure to be kept until a reload")
+	}
+
+	// As Load does.
+	shader.loads++
+	su, err := uc.get(shader)
+	if err != nil || su.color.Location() != 1 {
+		t.Errorf("Expected the uniforms to resolve after a reload, got: %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package rendering
+
+import (
+	"github.com/wdevore/ranger/graphics"
+)
+
+// VectorColorAtlas defines an atlas whose vertices each have a colour,
+// for example, for gradients. Draw it with the vector_color shader.
+type VectorColorAtlas struct {
+	VectorAtlas
+}
+
+// NewVectorColorAtlas creates a new per-vertex colour atlas
+func NewVectorColorAtlas(isStatic bool) *VectorColorAtlas {
+	vca := new(VectorColorAtlas)
+	vca.Initialize(isStatic, true)
+	return vca
+}
+
+// Add adds a coloured vertex and an index
+func (vca *VectorColorAtlas) Add(x, y, z float32, color *graphics.Colors, index int) {
+	vca.AddVertexComponents(x, y, z, color.R, color.G, color.B, color.A)
+	vca.AddIndex(index)
+}
+
+// Add2Component adds a coloured vertex and auto generated index
+func (vca *VectorColorAtlas) Add2Component(x, y float32, color *graphics.Colors) {
+	vca.Add(x, y, 0.0, color, vca.Idx)
+}
+
+// Add3Component adds a coloured vertex and auto generated index
+func (vca *VectorColorAtlas) Add3Component(x, y, z float32, color *graphics.Colors) {
+	vca.Add(x, y, z, color, vca.Idx)
+}
@@ -3,7 +3,9 @@ package rendering
 // VectorObject associates an Atlas with a VAO
 type VectorObject struct {
 	UniAtlas *VectorUniformAtlas
-	vao      *VAO
+	// ColorAtlas is used instead of UniAtlas after ConstructColored.
+	ColorAtlas *VectorColorAtlas
+	vao        *VAO
 }
 
 // NewVectorObject creates a new vector object with an associated Mesh
@@ -18,6 +20,13 @@ func (vo *VectorObject) Construct() {
 	vo.vao = NewVAO(&vo.UniAtlas.mesh)
 }
 
+// ConstructColored configures a vector object whose vertices each have
+// a colour.
+func (vo *VectorObject) ConstructColored() {
+	vo.ColorAtlas = NewVectorColorAtlas(true)
+	vo.vao = NewVAO(&vo.ColorAtlas.mesh)
+}
+
 // Use activates the VAO
 func (vo *VectorObject) Use() {
 	vo.vao.Use()
@@ -28,6 +37,25 @@ func (vo *VectorObject) Bind() {
 	vo.vao.Bind()
 }
 
+// Update uploads the atlas after it was appended to or rebuilt.
+func (vo *VectorObject) Update() {
+	vo.vao.Update()
+}
+
+// UpdateVertices uploads 'count' vertices, starting at 'first', that were
+// changed in place.
+func (vo *VectorObject) UpdateVertices(first, count int) {
+	vo.vao.UpdateVertices(first, count)
+}
+
+// Delete deletes the VAO and buffers. The atlas is kept so Bind uploads
+// it again.
+func (vo *VectorObject) Delete() {
+	if vo.vao != nil {
+		vo.vao.Delete()
+	}
+}
+
 // Render renders the given shape using the currently activated VAO
 func (vo *VectorObject) Render(vs *VectorShape) {
 	vo.vao.Render(vs)
@@ -0,0 +1,50 @@
+// Package rendering defines a uniform colored VectorShape renderer.
+package rendering
+
+import (
+	"github.com/wdevore/ranger/graphics"
+	"github.com/wdevore/ranger/rmath"
+)
+
+// VectorShapeRenderer draws VectorShapes of a VectorObject in a single
+// colour. It expects a shader with:
+//   attribute 0: vec3 position
+//   uniform mat4 mvp
+//   uniform vec4 color
+type VectorShapeRenderer struct {
+	shader *Shader
+
+	mvp   Uniform
+	color Uniform
+}
+
+// NewVectorShapeRenderer creates a renderer using a vector shader. You must
+// call Construct before drawing.
+func NewVectorShapeRenderer(shader *Shader) *VectorShapeRenderer {
+	vr := new(VectorShapeRenderer)
+	vr.shader = shader
+	return vr
+}
+
+// Construct resolves the shader's uniforms. The shader must already be loaded.
+func (vr *VectorShapeRenderer) Construct() error {
+	uniforms, err := vr.shader.Uniforms("mvp", "color")
+	if err != nil {
+		return err
+	}
+	vr.mvp, vr.color = uniforms[0], uniforms[1]
+
+	return nil
+}
+
+// Draw renders a shape of the vector object. The object must have been
+// bound (uploaded).
+func (vr *VectorShapeRenderer) Draw(vo *VectorObject, shape *VectorShape, mvp *rmath.Matrix4, color *graphics.Colors) {
+	vr.shader.Use()
+
+	vr.mvp.SetMatrix4(mvp)
+	vr.color.SetColor(color)
+
+	vo.Use()
+	vo.Render(shape)
+}
@@ -0,0 +1,194 @@
+// Package rendering defines vertex layouts.
+package rendering
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/ranger/graphics"
+)
+
+// VertexAttribute describes one attribute of a vertex.
+type VertexAttribute struct {
+	// Name of the shader's input, for example, "aPos".
+	Name string
+	// Index is the attribute's location, see "layout (location = n)".
+	Index uint32
+	// Components is 1 to 4.
+	Components int32
+	// Type of each component, for example, gl.FLOAT or gl.UNSIGNED_BYTE.
+	Type uint32
+	// Normalized maps integer types to 0 -> 1 (or -1 -> 1).
+	Normalized bool
+	// Divisor advances the attribute once per 'Divisor' instances instead
+	// of once per vertex. 0 is per vertex.
+	Divisor uint32
+}
+
+// Standard attributes at the locations the built-in shaders use.
+var (
+	PositionAttribute = VertexAttribute{Name: "aPos", Index: 0, Components: 3, Type: gl.FLOAT}
+	UVAttribute       = VertexAttribute{Name: "aUV", Index: 1, Components: 2, Type: gl.FLOAT}
+	ColorAttribute    = VertexAttribute{Name: "aColor", Index: 2, Components: 4, Type: gl.FLOAT}
+	NormalAttribute   = VertexAttribute{Name: "aNormal", Index: 3, Components: 3, Type: gl.FLOAT}
+
+	// PackedColorAttribute is an RGBA colour packed into one float32 slot
+	// by PackColor.
+	PackedColorAttribute = VertexAttribute{Name: "aColor", Index: 2, Components: 4, Type: gl.UNSIGNED_BYTE, Normalized: true}
+
+	// Per instance attributes: the rows of a 2D affine transform and a
+	// colour.
+	InstanceRow0Attribute  = VertexAttribute{Name: "iRow0", Index: 4, Components: 3, Type: gl.FLOAT, Divisor: 1}
+	InstanceRow1Attribute  = VertexAttribute{Name: "iRow1", Index: 5, Components: 3, Type: gl.FLOAT, Divisor: 1}
+	InstanceColorAttribute = VertexAttribute{Name: "iColor", Index: 6, Components: 4, Type: gl.FLOAT, Divisor: 1}
+)
+
+// Size returns the attribute's size in bytes.
+func (va *VertexAttribute) Size() int32 {
+	return va.Components * typeSize(va.Type)
+}
+
+// VertexFormat describes the attributes of a Mesh's vertices. Meshes store
+// float32s so each attribute must be a multiple of 4 bytes.
+//
+// Interleaved vertices store each vertex's attributes together:
+//   x,y,z,u,v, x,y,z,u,v, ...
+// otherwise each attribute is a separate stream of all vertices:
+//   x,y,z, x,y,z, ..., u,v, u,v, ...
+type VertexFormat struct {
+	Attributes  []VertexAttribute
+	Interleaved bool
+}
+
+// Standard formats.
+var (
+	PositionFormat      = NewVertexFormat(true, PositionAttribute)
+	PositionUVFormat    = NewVertexFormat(true, PositionAttribute, UVAttribute)
+	PositionColorFormat = NewVertexFormat(true, PositionAttribute, ColorAttribute)
+
+	// InstanceFormat is the layout of an InstanceBuffer.
+	InstanceFormat = NewVertexFormat(true, InstanceRow0Attribute, InstanceRow1Attribute, InstanceColorAttribute)
+)
+
+// NewVertexFormat creates a format. It panics if an attribute isn't a
+// multiple of 4 bytes.
+func NewVertexFormat(interleaved bool, attributes ...VertexAttribute) *VertexFormat {
+	vf := new(VertexFormat)
+	vf.Interleaved = interleaved
+	vf.Attributes = attributes
+
+	err := vf.Validate()
+	if err != nil {
+		panic(err)
+	}
+
+	return vf
+}
+
+// Validate checks the attributes can be stored in a Mesh.
+func (vf *VertexFormat) Validate() error {
+	if len(vf.Attributes) == 0 {
+		return fmt.Errorf("vertex format has no attributes")
+	}
+
+	for _, a := range vf.Attributes {
+		if a.Components < 1 || a.Components > 4 {
+			return fmt.Errorf("attribute '%s' has %d components, expected 1 to 4", a.Name, a.Components)
+		}
+		if typeSize(a.Type) == 0 {
+			return fmt.Errorf("attribute '%s' has an unsupported type: 0x%x", a.Name, a.Type)
+		}
+		if a.Size()%4 != 0 {
+			return fmt.Errorf("attribute '%s' is %d bytes, expected a multiple of 4", a.Name, a.Size())
+		}
+	}
+
+	return nil
+}
+
+// ComponentCount returns how many float32s a vertex takes.
+func (vf *VertexFormat) ComponentCount() int {
+	count := int32(0)
+	for i := range vf.Attributes {
+		count += vf.Attributes[i].Size()
+	}
+	return int(count / 4)
+}
+
+// Stride returns the bytes between a vertex and the next in an attribute's
+// stream.
+func (vf *VertexFormat) Stride(attribute int) int32 {
+	if vf.Interleaved {
+		return int32(vf.ComponentCount() * 4)
+	}
+	return vf.Attributes[attribute].Size()
+}
+
+// Offset returns the byte offset of an attribute's first value.
+func (vf *VertexFormat) Offset(attribute, vertexCount int) int {
+	offset := 0
+	for i := 0; i < attribute; i++ {
+		size := int(vf.Attributes[i].Size())
+		if !vf.Interleaved {
+			size *= vertexCount
+		}
+		offset += size
+	}
+	return offset
+}
+
+// Attribute returns the named attribute or nil.
+func (vf *VertexFormat) Attribute(name string) *VertexAttribute {
+	for i := range vf.Attributes {
+		if vf.Attributes[i].Name == name {
+			return &vf.Attributes[i]
+		}
+	}
+	return nil
+}
+
+// attributeIndex returns the position of the named attribute or -1.
+func (vf *VertexFormat) attributeIndex(name string) int {
+	for i := range vf.Attributes {
+		if vf.Attributes[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// apply records the attribute pointers, for the bound VAO and vertex
+// buffer, of 'vertexCount' vertices.
+func (vf *VertexFormat) apply(vertexCount int) {
+	for i, a := range vf.Attributes {
+		gl.VertexAttribPointer(a.Index, a.Components, a.Type, a.Normalized,
+			vf.Stride(i), gl.PtrOffset(vf.Offset(i, vertexCount)))
+		gl.EnableVertexAttribArray(a.Index)
+		if a.Divisor > 0 {
+			gl.VertexAttribDivisor(a.Index, a.Divisor)
+		}
+	}
+}
+
+// PackColor packs a colour into a float32's bits for PackedColorAttribute.
+func PackColor(c *graphics.Colors) float32 {
+	r := uint32(c.R*255.0 + 0.5)
+	g := uint32(c.G*255.0 + 0.5)
+	b := uint32(c.B*255.0 + 0.5)
+	a := uint32(c.A*255.0 + 0.5)
+	// Memory order is r,g,b,a on little endian machines.
+	return math.Float32frombits(a<<24 | b<<16 | g<<8 | r)
+}
+
+func typeSize(xtype uint32) int32 {
+	switch xtype {
+	case gl.FLOAT, gl.INT, gl.UNSIGNED_INT:
+		return 4
+	case gl.HALF_FLOAT, gl.SHORT, gl.UNSIGNED_SHORT:
+		return 2
+	case gl.BYTE, gl.UNSIGNED_BYTE:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,107 @@
+package rendering
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/ranger/graphics"
+)
+
+func Test_VertexFormat_Interleaved(t *testing.T) {
+	vf := NewVertexFormat(true, PositionAttribute, UVAttribute, ColorAttribute)
+
+	if vf.ComponentCount() != 9 {
+		t.Errorf("Expected 9 components, got: %d", vf.ComponentCount())
+	}
+
+	for i := range vf.Attributes {
+		if vf.Stride(i) != 36 {
+			t.Errorf("Expected attribute %d stride 36, got: %d", i, vf.Stride(i))
+		}
+	}
+
+	expected := []int{0, 12, 20}
+	for i, e := range expected {
+		if got := vf.Offset(i, 10); got != e {
+			t.Errorf("Expected attribute %d offset %d, got: %d", i, e, got)
+		}
+	}
+}
+
+func Test_VertexFormat_Separate(t *testing.T) {
+	vf := NewVertexFormat(false, PositionAttribute, PackedColorAttribute)
+
+	if vf.ComponentCount() != 4 {
+		t.Errorf("Expected 4 components, got: %d", vf.ComponentCount())
+	}
+
+	if vf.Stride(0) != 12 || vf.Stride(1) != 4 {
+		t.Errorf("Expected strides 12 and 4, got: %d %d", vf.Stride(0), vf.Stride(1))
+	}
+
+	// Colours follow 5 positions.
+	if vf.Offset(1, 5) != 60 {
+		t.Errorf("Expected the colour stream at 60, got: %d", vf.Offset(1, 5))
+	}
+}
+
+func Test_VertexFormat_Validate(t *testing.T) {
+	bad := []*VertexFormat{
+		{Attributes: []VertexAttribute{}},
+		{Attributes: []VertexAttribute{{Name: "a", Components: 5, Type: gl.FLOAT}}},
+		{Attributes: []VertexAttribute{{Name: "a", Components: 3, Type: gl.UNSIGNED_BYTE}}},
+		{Attributes: []VertexAttribute{{Name: "a", Components: 2, Type: gl.DOUBLE}}},
+	}
+
+	for i, vf := range bad {
+		if vf.Validate() == nil {
+			t.Errorf("Expected format %d to be invalid", i)
+		}
+	}
+}
+
+func Test_PackColor(t *testing.T) {
+	c := graphics.NewColors().Set(1.0, 0.5, 0.0, 1.0)
+	bits := math.Float32bits(PackColor(c))
+
+	if bits != 0xff0080ff {
+		t.Errorf("Expected 0xff0080ff, got: 0x%x", bits)
+	}
+}
+
+func Test_VectorColorAtlas(t *testing.T) {
+	vca := NewVectorColorAtlas(true)
+
+	if !vca.HasColors() || vca.HasNormals() {
+		t.Errorf("Expected colours and no normals")
+	}
+
+	vca.Add2Component(1.0, 2.0, graphics.Red)
+	vca.Add3Component(3.0, 4.0, 5.0, graphics.Blue)
+
+	expected := []float32{
+		1.0, 2.0, 0.0, graphics.Red.R, graphics.Red.G, graphics.Red.B, graphics.Red.A,
+		3.0, 4.0, 5.0, graphics.Blue.R, graphics.Blue.G, graphics.Blue.B, graphics.Blue.A,
+	}
+
+	if len(vca.mesh.Vertices) != len(expected) {
+		t.Fatalf("Expected %d components, got: %d", len(expected), len(vca.mesh.Vertices))
+	}
+
+	for i, e := range expected {
+		if vca.mesh.Vertices[i] != e {
+			t.Errorf("Expected component %d = %f, got: %f", i, e, vca.mesh.Vertices[i])
+		}
+	}
+
+	if vca.mesh.VertexCount() != 2 || vca.Idx != 2 {
+		t.Errorf("Expected 2 vertices and indices, got: %d %d", vca.mesh.VertexCount(), vca.Idx)
+	}
+
+	// Plain vertices are padded to the format.
+	vca.AddVertex(6.0, 7.0, 8.0)
+	if vca.mesh.VertexCount() != 3 {
+		t.Errorf("Expected 3 vertices, got: %d", vca.mesh.VertexCount())
+	}
+}
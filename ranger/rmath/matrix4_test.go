@@ -24,7 +24,7 @@ func Test_Identity(t *testing.T) {
 
 func Test_Translate(t *testing.T) {
 	m := NewMatrix4()
-	m.SetTranslate(NewVector3With2Components(5.0, 6.0))
+	m.SetTranslate3Comp(5.0, 6.0, 0.0)
 
 	// Note: in order for this log to show in the Output window you need to
 	// add "go.testFlags": ["-v"] to your User-Settings json file in Visual Studio Code.
@@ -37,3 +37,35 @@ func Test_Translate(t *testing.T) {
 		t.Error("Expected m13 = 6.0")
 	}
 }
+
+func Test_Invert(t *testing.T) {
+	m := NewMatrix4()
+	m.SetToOrtho(-50.0, 50.0, -25.0, 25.0, 0.1, 100.0)
+	m.TranslateBy3Comps(5.0, -3.0, 0.0)
+
+	inv := NewMatrix4()
+	if !inv.Invert(m) {
+		t.Fatal("Expected matrix to be invertible")
+	}
+
+	v := NewVector3With3Components(12.0, 7.0, -1.0)
+	v.Mul(m).Mul(inv)
+
+	if !IsEqual(v.X, 12.0) || !IsEqual(v.Y, 7.0) || !IsEqual(v.Z, -1.0) {
+		t.Errorf("Expected <12, 7, -1>, got: %s", v)
+	}
+}
+
+func Test_InvertSingular(t *testing.T) {
+	m := NewMatrix4()
+	m.SetScale3Comp(1.0, 0.0, 1.0)
+
+	inv := NewMatrix4()
+	if inv.Invert(m) {
+		t.Error("Expected singular matrix to fail inversion")
+	}
+
+	if inv.C(M00) != 1.0 {
+		t.Error("Expected target to be unmodified")
+	}
+}
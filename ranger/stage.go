@@ -3,6 +3,8 @@ package ranger
 import (
 	"github.com/go-gl/gl/v4.5-core/gl"
 	"github.com/wdevore/ranger/config"
+	"github.com/wdevore/ranger/graphics"
+	"github.com/wdevore/ranger/rendering"
 	"github.com/wdevore/ranger/rmath"
 )
 
@@ -26,8 +28,14 @@ func (st *Stage) Initialize(e *Engine) {
 	st.viewProjection.Set(&e.Camera.Matrix)
 	st.viewProjection.PostMultiply(&e.View.Matrix)
 
-	gl.Enable(gl.BLEND)
-	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	rendering.BlendAlpha.Apply()
+	graphics.GLState.PolygonMode(gl.FILL)
+}
+
+// ViewProjection returns the camera's projection times the view, ready to
+// be set on a shader's Uniform.
+func (st *Stage) ViewProjection() *rmath.Matrix4 {
+	return &st.viewProjection
 }
 
 // Settings returns the engine's configuration settings.